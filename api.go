@@ -116,7 +116,7 @@ func (c *CGMiner) AddPoolContext(ctx context.Context, url, username, password st
 	// TODO: Escape commas in the URL, username, and password
 	resp := new(GenericResponse)
 	parameter := fmt.Sprintf("%s,%s,%s", url, username, password)
-	return c.CallContext(ctx, NewCommand("pools", parameter), resp)
+	return c.CallContext(ctx, NewCommand("addpool", parameter), resp)
 }
 
 func (c *CGMiner) EnablePool(pool *Pool) error {
@@ -136,11 +136,21 @@ func (c *CGMiner) DisablePoolContext(ctx context.Context, pool *Pool) error {
 }
 
 func (c *CGMiner) RemovePool(pool *Pool) error {
-	return c.Call(NewCommand("removepool", strconv.FormatInt(pool.Pool, 10)), nil)
+	return c.RemovePoolContext(context.Background(), pool)
+}
+
+// RemovePoolContext removes pool from miner's pool list
+func (c *CGMiner) RemovePoolContext(ctx context.Context, pool *Pool) error {
+	return c.CallContext(ctx, NewCommand("removepool", strconv.FormatInt(pool.Pool, 10)), nil)
 }
 
 func (c *CGMiner) SwitchPool(pool *Pool) error {
-	return c.Call(NewCommand("switchpool", strconv.FormatInt(pool.Pool, 10)), nil)
+	return c.SwitchPoolContext(context.Background(), pool)
+}
+
+// SwitchPoolContext switches pool to passed one
+func (c *CGMiner) SwitchPoolContext(ctx context.Context, pool *Pool) error {
+	return c.CallContext(ctx, NewCommand("switchpool", strconv.FormatInt(pool.Pool, 10)), nil)
 }
 
 // DevDetailContext returns a slice of DeviceDetail structs.
@@ -158,12 +168,79 @@ func (c *CGMiner) DevDetails() ([]DeviceDetail, error) {
 	return c.DevDetailContext(context.Background())
 }
 
+// Config returns miner configuration. See the Config struct.
+func (c *CGMiner) Config() (*Config, error) {
+	return c.ConfigContext(context.Background())
+}
+
+// ConfigContext returns miner configuration. See the Config struct.
+func (c *CGMiner) ConfigContext(ctx context.Context) (*Config, error) {
+	resp := new(configResponse)
+	if err := c.CallContext(ctx, NewCommandWithoutParameter("config"), resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Config) < 1 {
+		return nil, errors.New("no config in JSON response")
+	}
+	if len(resp.Config) > 1 {
+		return nil, errors.New("too many configs in JSON response")
+	}
+	return &resp.Config[0], nil
+}
+
+// Coin returns currently mined coin info. See the Coin struct.
+func (c *CGMiner) Coin() (*Coin, error) {
+	return c.CoinContext(context.Background())
+}
+
+// CoinContext returns currently mined coin info. See the Coin struct.
+func (c *CGMiner) CoinContext(ctx context.Context) (*Coin, error) {
+	resp := new(coinResponse)
+	if err := c.CallContext(ctx, NewCommandWithoutParameter("coin"), resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Coin) < 1 {
+		return nil, errors.New("no coin in JSON response")
+	}
+	if len(resp.Coin) > 1 {
+		return nil, errors.New("too many coins in JSON response")
+	}
+	return &resp.Coin[0], nil
+}
+
+// Notify returns a slice of Notify structs, one per device.
+func (c *CGMiner) Notify() ([]Notify, error) {
+	return c.NotifyContext(context.Background())
+}
+
+// NotifyContext returns a slice of Notify structs, one per device.
+func (c *CGMiner) NotifyContext(ctx context.Context) ([]Notify, error) {
+	resp := new(notifyResponse)
+	if err := c.CallContext(ctx, NewCommandWithoutParameter("notify"), resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Notify, nil
+}
+
 func (c *CGMiner) Restart() error {
-	return c.Call(NewCommandWithoutParameter("restart"), nil)
+	return c.RestartContext(context.Background())
+}
+
+// RestartContext restarts miner
+func (c *CGMiner) RestartContext(ctx context.Context) error {
+	return c.CallContext(ctx, NewCommandWithoutParameter("restart"), nil)
 }
 
 func (c *CGMiner) Quit() error {
-	return c.Call(NewCommandWithoutParameter("quit"), nil)
+	return c.QuitContext(context.Background())
+}
+
+// QuitContext stops miner
+func (c *CGMiner) QuitContext(ctx context.Context) error {
+	return c.CallContext(ctx, NewCommandWithoutParameter("quit"), nil)
 }
 
 // CheckAvailableCommands - check all commands, that supported by device
@@ -0,0 +1,251 @@
+// Package apiserver serves cgminer API protocol backed by single upstream miner.
+//
+// Commands are forwarded upstream if Server.Forward allows them, only
+// ReadCommands are allowed by default. Set Upstream.Cache to answer read commands
+// from cache, so many monitoring tools can poll server without hammering
+// the miner.
+//
+// Both JSON ({"command":"summary"}) and plain-text ("summary|") requests
+// are accepted, responses are encoded in the same format as request.
+// JSON requests may join read commands with "+", e.g. "summary+devs".
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// DefaultReadTimeout is default timeout of reading client request
+const DefaultReadTimeout = 10 * time.Second
+
+// ReadCommands are commands allowed by default. Commands not listed here,
+// including unknown ones and privileged commands like "debug" or "hotplug",
+// are denied unless Server.Forward allows them.
+var ReadCommands = []string{
+	"summary", "devs", "pools", "stats", "version", "config",
+	"devdetails", "coin", "gpu", "gpucount", "notify", "check",
+}
+
+// ErrServerClosed is returned by Serve after Close
+var ErrServerClosed = errors.New("apiserver: server closed")
+
+// Status codes of responses generated by server.
+// Codes match cgminer ones where cgminer has equivalent.
+const (
+	// CodeInvalidCommand is returned for malformed requests
+	CodeInvalidCommand = 14
+
+	// CodeAccessDenied is returned for commands not allowed by Server.Forward
+	CodeAccessDenied = 45
+
+	// CodeUpstreamError is returned when upstream miner is unreachable
+	// or returned malformed response
+	CodeUpstreamError = 999
+)
+
+// Server is cgminer API protocol server.
+type Server struct {
+	// Upstream is miner which serves requests
+	Upstream *cgminer.CGMiner
+
+	// Forward reports whether command is allowed to be forwarded upstream.
+	//
+	// If nil, only ReadCommands are allowed.
+	Forward func(cmd cgminer.Command) bool
+
+	// ReadTimeout limits time of reading client request,
+	// DefaultReadTimeout is used if zero.
+	ReadTimeout time.Duration
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+
+	// ctx is canceled on Close to abort upstream requests
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewServer returns server for upstream miner
+func NewServer(upstream *cgminer.CGMiner) *Server {
+	return &Server{Upstream: upstream}
+}
+
+// ListenAndServe listens on TCP address and serves connections
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on listener and serves them until
+// listener fails or server is closed.
+//
+// Serve always returns non-nil error, ErrServerClosed after Close.
+func (s *Server) Serve(l net.Listener) error {
+	if !s.track(l) {
+		_ = l.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				time.Sleep(50 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !s.trackConn(conn) {
+			_ = conn.Close()
+			return ErrServerClosed
+		}
+		go func() {
+			defer s.wg.Done()
+			defer s.untrackConn(conn)
+			s.serveConn(s.ctx, conn)
+		}()
+	}
+}
+
+// Close closes listeners and active connections
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	var err error
+	for l := range s.listeners {
+		if cerr := l.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) track(l net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[net.Listener]struct{})
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+func (s *Server) untrack(l net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.listeners, l)
+}
+
+func (s *Server) trackConn(c net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) untrackConn(c net.Conn) {
+	_ = c.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, c)
+}
+
+func (s *Server) readTimeout() time.Duration {
+	if s.ReadTimeout > 0 {
+		return s.ReadTimeout
+	}
+	return DefaultReadTimeout
+}
+
+func (s *Server) allowed(cmd cgminer.Command) bool {
+	if s.Forward != nil {
+		return s.Forward(cmd)
+	}
+	for _, c := range ReadCommands {
+		if c == cmd.Command {
+			return true
+		}
+	}
+	return false
+}
+
+// execute returns response to single command
+func (s *Server) execute(ctx context.Context, cmd cgminer.Command) *cgminer.Response {
+	if !s.allowed(cmd) {
+		return errorResponse(CodeAccessDenied, "Access denied to '"+cmd.Command+"' command")
+	}
+
+	resp, err := s.call(ctx, cmd)
+	if err != nil {
+		return errorResponse(CodeUpstreamError, "Upstream error: "+err.Error())
+	}
+	return resp
+}
+
+// call sends command upstream. Upstream error status is returned
+// as response, not as error.
+func (s *Server) call(ctx context.Context, cmd cgminer.Command) (*cgminer.Response, error) {
+	resp := new(cgminer.Response)
+	err := s.Upstream.CallContext(ctx, cmd, resp)
+	if err != nil && len(resp.Status) == 0 {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// errorResponse returns response with error status
+func errorResponse(code int, msg string) *cgminer.Response {
+	return &cgminer.Response{
+		ID: 1,
+		Status: []cgminer.Status{{
+			Status:      "E",
+			When:        int(time.Now().Unix()),
+			Code:        code,
+			Msg:         msg,
+			Description: "apiserver",
+		}},
+	}
+}
@@ -0,0 +1,340 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// fakeMiner answers API commands through in-memory connections
+type fakeMiner struct {
+	mu       sync.Mutex
+	commands []string
+	down     bool
+	delay    time.Duration
+}
+
+func (f *fakeMiner) miner() *cgminer.CGMiner {
+	m := cgminer.NewCGMiner("10.0.0.1", 4028, 5*time.Second)
+	m.Dialer = cgminer.DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		f.mu.Lock()
+		down := f.down
+		f.mu.Unlock()
+		if down {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+		}
+
+		client, server := net.Pipe()
+		go f.serve(server)
+		return client, nil
+	})
+	return m
+}
+
+func (f *fakeMiner) serve(conn net.Conn) {
+	defer conn.Close()
+	var cmd cgminer.Command
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	f.commands = append(f.commands, cmd.Command+"|"+cmd.Parameter)
+	delay := f.delay
+	f.mu.Unlock()
+	time.Sleep(delay)
+
+	status := `"STATUS":[{"STATUS":"S","When":1,"Code":11,"Msg":"OK","Description":"trm"}],"id":1`
+	var rsp string
+	switch cmd.Command {
+	case "version":
+		rsp = `{` + status + `,"VERSION":[{"CGMiner":"4.0","API":"3.7","Miner":"TeamRedMiner 0.10"}]}`
+	case "summary":
+		rsp = `{` + status + `,"SUMMARY":[{"Elapsed":100,"MHS av":12.5,"Accepted":7}]}`
+	case "devs":
+		rsp = `{` + status + `,"DEVS":[{"GPU":0,"MHS av":6.25},{"GPU":1,"MHS av":6.25}]}`
+	case "pools":
+		rsp = `{` + status + `,"POOLS":[{"POOL":0,"URL":"stratum+tcp://a:3333"}]}`
+	case "coin":
+		rsp = `{` + status + `,"COIN":[{"Hash Method":"ethash"}]}`
+	case "removepool":
+		rsp = `{"STATUS":[{"STATUS":"E","When":1,"Code":66,"Msg":"Cannot remove active pool"}],"id":1}`
+	default:
+		rsp = `{` + status + `}`
+	}
+	_, _ = conn.Write(append([]byte(rsp), 0x00))
+}
+
+func (f *fakeMiner) calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+func (f *fakeMiner) count(command string) int {
+	n := 0
+	for _, c := range f.calls() {
+		if strings.HasPrefix(c, command+"|") {
+			n++
+		}
+	}
+	return n
+}
+
+// cachedMiner returns upstream with response cache
+func (f *fakeMiner) cachedMiner() *cgminer.CGMiner {
+	m := f.miner()
+	m.Cache = cgminer.NewResponseCache(time.Minute)
+	m.Cache.CommandTTL = map[string]time.Duration{"coin": 0}
+	return m
+}
+
+func startServer(t *testing.T, s *Server) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(l) }()
+	t.Cleanup(func() {
+		_ = s.Close()
+		if err := <-done; err != ErrServerClosed {
+			t.Errorf("Serve returned %v, want ErrServerClosed", err)
+		}
+	})
+	return l.Addr().String()
+}
+
+func client(t *testing.T, addr string) *cgminer.CGMiner {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p int
+	if err := json.Unmarshal([]byte(port), &p); err != nil {
+		t.Fatal(err)
+	}
+	return cgminer.NewCGMiner(host, p, 5*time.Second)
+}
+
+// rawCall sends raw request, closes write side as nc does
+// and returns response without null terminator
+func rawCall(t *testing.T, addr, req string) string {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.(*net.TCPConn).CloseWrite()
+	rsp, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimRight(string(rsp), "\x00")
+}
+
+func TestServer_Cache(t *testing.T) {
+	fake := &fakeMiner{}
+	addr := startServer(t, NewServer(fake.cachedMiner()))
+	c := client(t, addr)
+
+	for i := 0; i < 3; i++ {
+		summary, err := c.Summary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.MHSav != 12.5 || summary.Elapsed != 100 {
+			t.Errorf("unexpected summary: %+v", summary)
+		}
+	}
+	if n := fake.count("summary"); n != 1 {
+		t.Errorf("want single upstream summary call, got %d", n)
+	}
+
+	version, err := c.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.Miner != "TeamRedMiner 0.10" {
+		t.Errorf("unexpected version: %+v", version)
+	}
+
+	devs, err := c.Devs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*devs) != 2 || (*devs)[1].GPU != 1 {
+		t.Errorf("unexpected devs: %+v", *devs)
+	}
+
+	pools, err := c.Pools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 1 || pools[0].URL != "stratum+tcp://a:3333" {
+		t.Errorf("unexpected pools: %+v", pools)
+	}
+
+	// not cached commands are forwarded every time
+	for i := 0; i < 2; i++ {
+		if _, err := c.Coin(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := fake.count("coin"); n != 2 {
+		t.Errorf("want 2 upstream coin calls, got %d", n)
+	}
+}
+
+func TestServer_SharedFetch(t *testing.T) {
+	fake := &fakeMiner{delay: 100 * time.Millisecond}
+	addr := startServer(t, NewServer(fake.cachedMiner()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client(t, addr).Summary(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := fake.count("summary"); n != 1 {
+		t.Errorf("want single upstream summary call, got %d", n)
+	}
+}
+
+func TestServer_Filter(t *testing.T) {
+	fake := &fakeMiner{}
+	s := NewServer(fake.cachedMiner())
+	addr := startServer(t, s)
+	c := client(t, addr)
+
+	err := c.Restart()
+	if err == nil || !strings.Contains(err.Error(), "Access denied to 'restart' command") {
+		t.Errorf("expected access denied, got %v", err)
+	}
+	if n := fake.count("restart"); n != 0 {
+		t.Errorf("restart was forwarded upstream")
+	}
+
+	// privileged commands missing from write list are denied as well
+	for _, name := range []string{"debug", "hotplug", "unknown"} {
+		rsp := rawCall(t, addr, `{"command":"`+name+`","parameter":"1"}`)
+		if !strings.Contains(rsp, `"Code":45`) {
+			t.Errorf("%s: expected access denied, got %s", name, rsp)
+		}
+	}
+	if calls := fake.calls(); len(calls) != 0 {
+		t.Errorf("commands were forwarded upstream: %v", calls)
+	}
+
+	s.Forward = func(cmd cgminer.Command) bool { return cmd.Command != "quit" }
+
+	if _, err := c.Pools(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SwitchPool(&cgminer.Pool{Pool: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Pools(); err != nil {
+		t.Fatal(err)
+	}
+	if n := fake.count("pools"); n != 2 {
+		t.Errorf("write command should invalidate cache, got %d pools calls", n)
+	}
+
+	// upstream error status is passed as is
+	err = c.RemovePool(&cgminer.Pool{Pool: 0})
+	if err == nil || !strings.Contains(err.Error(), "Cannot remove active pool") {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+
+	if err := c.Quit(); err == nil || !strings.Contains(err.Error(), "Code: 45") {
+		t.Errorf("expected access denied, got %v", err)
+	}
+}
+
+func TestServer_Protocols(t *testing.T) {
+	fake := &fakeMiner{}
+	addr := startServer(t, NewServer(fake.miner()))
+
+	text := client(t, addr)
+	text.Transport = cgminer.NewTextTransport()
+	summary, err := text.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.MHSav != 12.5 || summary.Accepted != 7 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+
+	rsp := rawCall(t, addr, "summary|\n")
+	if !strings.HasPrefix(rsp, "STATUS=S,When=1,Code=11,Msg=OK,Description=trm|SUMMARY,") ||
+		!strings.Contains(rsp, ",MHS av=12.5") {
+		t.Errorf("unexpected text response: %q", rsp)
+	}
+
+	var joined struct {
+		Summary []map[string]json.RawMessage `json:"summary"`
+		Devs    []map[string]json.RawMessage `json:"devs"`
+	}
+	rsp = rawCall(t, addr, `{"command":"summary+devs","parameter":0}`)
+	if err := json.Unmarshal([]byte(rsp), &joined); err != nil {
+		t.Fatalf("%v: %s", err, rsp)
+	}
+	if len(joined.Summary) != 1 || len(joined.Devs) != 1 {
+		t.Fatalf("unexpected joined response: %s", rsp)
+	}
+	if _, ok := joined.Devs[0]["DEVS"]; !ok {
+		t.Errorf("no DEVS section in joined response: %s", rsp)
+	}
+
+	rsp = rawCall(t, addr, `{"command":"summary+restart"}`)
+	if !strings.Contains(rsp, `"Code":45`) {
+		t.Errorf("joined write command should be denied: %s", rsp)
+	}
+
+	rsp = rawCall(t, addr, `{"command":`)
+	if !strings.Contains(rsp, `"Code":14`) {
+		t.Errorf("expected invalid command status: %s", rsp)
+	}
+}
+
+func TestServer_UpstreamDown(t *testing.T) {
+	fake := &fakeMiner{down: true}
+	addr := startServer(t, NewServer(fake.miner()))
+
+	_, err := client(t, addr).Summary()
+	if err == nil || !strings.Contains(err.Error(), "Code: 999") {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+
+	fake.mu.Lock()
+	fake.down = false
+	fake.mu.Unlock()
+
+	// failures are not cached
+	if _, err := client(t, addr).Summary(); err != nil {
+		t.Error(err)
+	}
+}
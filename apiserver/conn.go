@@ -0,0 +1,201 @@
+package apiserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// maxRequestSize limits client request size
+const maxRequestSize = 8 << 10
+
+// writeTimeout limits time of writing response to client
+const writeTimeout = 10 * time.Second
+
+// jsonRequest is JSON API request.
+//
+// Parameter is kept raw, as some tools send numeric parameters.
+type jsonRequest struct {
+	Command   string          `json:"command"`
+	Parameter json.RawMessage `json:"parameter"`
+}
+
+// serveConn reads single request and writes response,
+// as cgminer closes connection after each reply.
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(s.readTimeout()))
+	r := bufio.NewReaderSize(io.LimitReader(conn, maxRequestSize), maxRequestSize)
+
+	first, err := peekNonSpace(r)
+	if err != nil {
+		return
+	}
+
+	var body []byte
+	if first == '{' {
+		body = s.serveJSON(ctx, r)
+	} else {
+		body = s.serveText(ctx, r)
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, _ = conn.Write(append(body, 0x00))
+}
+
+// peekNonSpace skips leading whitespace and returns first request byte
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, r.UnreadByte()
+		}
+	}
+}
+
+func (s *Server) serveJSON(ctx context.Context, r *bufio.Reader) []byte {
+	var req jsonRequest
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		return encodeJSON(errorResponse(CodeInvalidCommand, "Invalid JSON"))
+	}
+
+	cmd := cgminer.Command{Command: strings.TrimSpace(req.Command), Parameter: rawParameter(req.Parameter)}
+	if cmd.Command == "" {
+		return encodeJSON(errorResponse(CodeInvalidCommand, "Invalid command"))
+	}
+	if !strings.Contains(cmd.Command, "+") {
+		return encodeJSON(s.execute(ctx, cmd))
+	}
+
+	// joined commands are answered as {"cmd1":[response],"cmd2":[response]}
+	names := strings.Split(cmd.Command, "+")
+	for _, name := range names {
+		if name == "" {
+			return encodeJSON(errorResponse(CodeInvalidCommand, "Invalid command"))
+		}
+		if cgminer.IsWriteCommand(name) {
+			return encodeJSON(errorResponse(CodeAccessDenied, "Access denied to '"+name+"' command"))
+		}
+	}
+
+	joined := map[string]interface{}{"id": 1}
+	for _, name := range names {
+		if _, ok := joined[name]; ok {
+			continue
+		}
+		resp := s.execute(ctx, cgminer.Command{Command: name, Parameter: cmd.Parameter})
+		joined[name] = []interface{}{responseObject(resp)}
+	}
+
+	body, err := json.Marshal(joined)
+	if err != nil {
+		return encodeJSON(errorResponse(CodeUpstreamError, "Upstream error: "+err.Error()))
+	}
+	return body
+}
+
+// rawParameter returns JSON request parameter as string
+func rawParameter(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+	return string(raw)
+}
+
+func (s *Server) serveText(ctx context.Context, r *bufio.Reader) []byte {
+	// as cgminer, take request from single read
+	req, _ := r.Peek(r.Buffered())
+	if i := bytes.IndexAny(req, "\x00\n"); i >= 0 {
+		req = req[:i]
+	}
+
+	name, param := string(bytes.TrimSpace(req)), ""
+	if i := strings.IndexByte(name, '|'); i >= 0 {
+		name, param = name[:i], name[i+1:]
+	}
+	if name == "" {
+		return encodeText(errorResponse(CodeInvalidCommand, "Invalid command"))
+	}
+	return encodeText(s.execute(ctx, cgminer.Command{Command: name, Parameter: param}))
+}
+
+// responseObject returns response as JSON object with
+// STATUS, sections and id keys
+func responseObject(resp *cgminer.Response) map[string]interface{} {
+	obj := make(map[string]interface{}, len(resp.Sections)+2)
+	for name, records := range resp.Sections {
+		obj[name] = records
+	}
+	obj["STATUS"] = resp.Status
+	obj["id"] = resp.ID
+	return obj
+}
+
+func encodeJSON(resp *cgminer.Response) []byte {
+	body, err := json.Marshal(responseObject(resp))
+	if err != nil {
+		body, _ = json.Marshal(responseObject(errorResponse(CodeUpstreamError, "Upstream error: "+err.Error())))
+	}
+	return body
+}
+
+// encodeText encodes response in plain-text format:
+// "STATUS=S,When=1,Code=11,Msg=Summary,Description=...|SUMMARY,Elapsed=10,...|"
+func encodeText(resp *cgminer.Response) []byte {
+	var buf bytes.Buffer
+	for _, st := range resp.Status {
+		buf.WriteString("STATUS=" + escapeText(st.Status))
+		buf.WriteString(",When=" + strconv.Itoa(st.When))
+		buf.WriteString(",Code=" + strconv.Itoa(st.Code))
+		buf.WriteString(",Msg=" + escapeText(st.Msg))
+		buf.WriteString(",Description=" + escapeText(st.Description))
+		buf.WriteByte('|')
+	}
+
+	names := make([]string, 0, len(resp.Sections))
+	for name := range resp.Sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, record := range resp.Sections[name] {
+			keys := make([]string, 0, len(record))
+			for k := range record {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			buf.WriteString(name)
+			for _, k := range keys {
+				buf.WriteString("," + escapeText(k) + "=" + escapeText(record[k].String()))
+			}
+			buf.WriteByte('|')
+		}
+	}
+	return buf.Bytes()
+}
+
+// textEscaper escapes separators as cgminer does in plain-text mode
+var textEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, `,`, `\,`, `=`, `\=`)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
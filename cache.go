@@ -0,0 +1,335 @@
+package cgminer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ResponseCache caches miner responses of read commands.
+//
+// Identical read commands sent to the same miner concurrently share single
+// request (singleflight), replies are cached for TTL. Write commands are never
+// cached and invalidate entries they affect, e.g. "addpool" drops "pools".
+//
+// Single cache might be shared by several CGMiner clients, entries
+// are keyed by miner address and transport, so clients talking to the
+// same miner in JSON and plain-text formats don't get each other's replies.
+// Set CGMiner.Cache to enable caching.
+type ResponseCache struct {
+	// TTL is lifetime of cached replies. Zero TTL disables caching,
+	// but concurrent requests are still shared.
+	TTL time.Duration
+
+	// CommandTTL overrides TTL for specific commands,
+	// zero or negative value disables caching of command.
+	CommandTTL map[string]time.Duration
+
+	mu        sync.Mutex
+	entries   map[responseKey]responseEntry
+	flights   map[responseKey]*flight
+	gen       map[string]uint64
+	lastSweep time.Time
+	stats     CacheStats
+}
+
+// CacheStats holds ResponseCache counters
+type CacheStats struct {
+	// Hits is number of replies served from cache
+	Hits uint64
+
+	// Misses is number of requests sent to miners
+	Misses uint64
+
+	// Shared is number of requests which joined in-flight identical request
+	Shared uint64
+
+	// Invalidations is number of entries dropped by write commands
+	// or Invalidate calls
+	Invalidations uint64
+
+	// Entries is number of cached replies, including expired ones
+	// not swept yet
+	Entries int
+}
+
+type responseKey struct {
+	miner     string
+	transport interface{}
+	cmd       Command
+}
+
+// transportKey identifies transport with its format options.
+// Transports which can't be compared are identified by type.
+func transportKey(t Transport) interface{} {
+	if t == nil || !reflect.TypeOf(t).Comparable() {
+		return reflect.TypeOf(t)
+	}
+	return t
+}
+
+type responseEntry struct {
+	raw     []byte
+	expires time.Time
+}
+
+// flight is in-flight request shared by concurrent callers
+type flight struct {
+	done chan struct{}
+	raw  []byte
+	err  error
+}
+
+// cacheInvalidates maps write command to read commands which replies
+// it affects. Write commands which are not listed invalidate all replies.
+var cacheInvalidates = map[string][]string{
+	"addpool":       {"pools", "config"},
+	"removepool":    {"pools", "config"},
+	"enablepool":    {"pools"},
+	"disablepool":   {"pools"},
+	"switchpool":    {"pools"},
+	"poolpriority":  {"pools", "config"},
+	"poolquota":     {"pools"},
+	"failover-only": {"config"},
+	"setconfig":     {"config"},
+	"gpuenable":     {"devs", "gpu"},
+	"gpudisable":    {"devs", "gpu"},
+	"gpuintensity":  {"devs", "gpu"},
+	"gpumem":        {"devs", "gpu"},
+	"gpuengine":     {"devs", "gpu"},
+	"gpufan":        {"devs", "gpu"},
+	"gpuvddc":       {"devs", "gpu"},
+	"gpupowertune":  {"devs", "gpu"},
+	"save":          {},
+}
+
+// NewResponseCache returns cache with passed replies lifetime
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{TTL: ttl}
+}
+
+// Stats returns cache counters
+func (rc *ResponseCache) Stats() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	s := rc.stats
+	s.Entries = len(rc.entries)
+	return s
+}
+
+// Invalidate drops cached replies of miner to passed commands,
+// or all miner replies if no command passed.
+func (rc *ResponseCache) Invalidate(miner *CGMiner, commands ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.invalidate(miner.endpointID(), commands)
+}
+
+// invalidate drops replies, nil commands drop all miner replies.
+// Caller must hold lock.
+func (rc *ResponseCache) invalidate(id string, commands []string) {
+	if rc.gen == nil {
+		rc.gen = make(map[string]uint64)
+	}
+	rc.gen[id]++
+
+	for key := range rc.entries {
+		if key.miner != id {
+			continue
+		}
+		if len(commands) > 0 && !contains(commands, key.cmd.Command) {
+			continue
+		}
+		delete(rc.entries, key)
+		rc.stats.Invalidations++
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (rc *ResponseCache) ttl(command string) time.Duration {
+	if ttl, ok := rc.CommandTTL[command]; ok {
+		return ttl
+	}
+	return rc.TTL
+}
+
+// call sends command through cache
+func (rc *ResponseCache) call(ctx context.Context, c *CGMiner, cmd Command, out AbstractResponse) error {
+	if IsWriteCommand(cmd.Command) {
+		err := c.call(ctx, cmd, out)
+
+		// miner state might be changed even if command failed
+		affected, ok := cacheInvalidates[cmd.Command]
+		if !ok || len(affected) > 0 {
+			rc.mu.Lock()
+			rc.invalidate(c.endpointID(), affected)
+			rc.mu.Unlock()
+		}
+		return err
+	}
+
+	raw, err := rc.fetch(ctx, c, cmd)
+	if err != nil {
+		return err
+	}
+	return c.Transport.DecodeResponse(newResponseConn(raw), cmd, out)
+}
+
+// fetch returns cached reply or requests it from miner
+func (rc *ResponseCache) fetch(ctx context.Context, c *CGMiner, cmd Command) ([]byte, error) {
+	key := responseKey{miner: c.endpointID(), transport: transportKey(c.Transport), cmd: cmd}
+	ttl := rc.ttl(cmd.Command)
+
+	for {
+		rc.mu.Lock()
+		if e, ok := rc.entries[key]; ok && ttl > 0 && time.Now().Before(e.expires) {
+			rc.stats.Hits++
+			rc.mu.Unlock()
+			return e.raw, nil
+		}
+
+		if f, ok := rc.flights[key]; ok {
+			rc.stats.Shared++
+			rc.mu.Unlock()
+
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if isContextError(f.err) && ctx.Err() == nil {
+				// request was canceled by its caller, try again with own context
+				continue
+			}
+			return f.raw, f.err
+		}
+
+		f := &flight{done: make(chan struct{})}
+		if rc.flights == nil {
+			rc.flights = make(map[responseKey]*flight)
+		}
+		rc.flights[key] = f
+		rc.stats.Misses++
+		gen := rc.gen[key.miner]
+		rc.mu.Unlock()
+
+		f.raw, f.err = c.fetch(ctx, cmd)
+		cacheable := f.err == nil && ttl > 0 && isSuccessReply(f.raw)
+
+		rc.mu.Lock()
+		delete(rc.flights, key)
+		// reply is dropped if miner was invalidated while request was in flight
+		if cacheable && rc.gen[key.miner] == gen {
+			rc.store(key, f.raw, ttl)
+		}
+		rc.mu.Unlock()
+		close(f.done)
+
+		return f.raw, f.err
+	}
+}
+
+// store saves reply and sweeps expired entries. Caller must hold lock.
+func (rc *ResponseCache) store(key responseKey, raw []byte, ttl time.Duration) {
+	now := time.Now()
+	if rc.entries == nil {
+		rc.entries = make(map[responseKey]responseEntry)
+	}
+	rc.entries[key] = responseEntry{raw: raw, expires: now.Add(ttl)}
+
+	if now.Sub(rc.lastSweep) < time.Second {
+		return
+	}
+	rc.lastSweep = now
+	for k, e := range rc.entries {
+		if now.After(e.expires) {
+			delete(rc.entries, k)
+		}
+	}
+}
+
+// statusSection matches STATUS section of JSON reply
+var statusSection = regexp.MustCompile(`"STATUS"\s*:\s*\[[^\]]*\]`)
+
+// isSuccessReply reports whether raw JSON or plain-text reply has
+// STATUS section without errors. Only STATUS section is decoded,
+// so response body schema doesn't matter.
+func isSuccessReply(raw []byte) bool {
+	raw = bytes.TrimSpace(raw)
+
+	var statuses []string
+	if bytes.HasPrefix(raw, []byte("{")) {
+		// STATUS section is cut out, as rest of reply might need repair
+		section := statusSection.Find(raw)
+		var status []struct {
+			Status string `json:"STATUS"`
+		}
+		if section == nil || json.Unmarshal(section[bytes.IndexByte(section, '['):], &status) != nil {
+			return false
+		}
+		for _, s := range status {
+			statuses = append(statuses, s.Status)
+		}
+	} else {
+		// plain-text reply starts with "STATUS=S,When=...|" section
+		section := raw
+		if i := bytes.IndexByte(raw, '|'); i != -1 {
+			section = raw[:i]
+		}
+		for _, kv := range bytes.Split(section, []byte(",")) {
+			if bytes.HasPrefix(kv, []byte("STATUS=")) {
+				statuses = append(statuses, string(kv[len("STATUS="):]))
+			}
+		}
+	}
+
+	for _, s := range statuses {
+		if s == "E" || s == "F" {
+			return false
+		}
+	}
+	return len(statuses) > 0
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+var errCachedWrite = errors.New("cgminer: cached response is read-only")
+
+// responseConn is read-only net.Conn over cached reply,
+// used to decode it with Transport.
+type responseConn struct {
+	*bytes.Reader
+}
+
+func newResponseConn(raw []byte) net.Conn {
+	return responseConn{Reader: bytes.NewReader(raw)}
+}
+
+func (responseConn) Write([]byte) (int, error)        { return 0, errCachedWrite }
+func (responseConn) Close() error                     { return nil }
+func (responseConn) LocalAddr() net.Addr              { return cachedAddr{} }
+func (responseConn) RemoteAddr() net.Addr             { return cachedAddr{} }
+func (responseConn) SetDeadline(time.Time) error      { return nil }
+func (responseConn) SetReadDeadline(time.Time) error  { return nil }
+func (responseConn) SetWriteDeadline(time.Time) error { return nil }
+
+type cachedAddr struct{}
+
+func (cachedAddr) Network() string { return "cache" }
+func (cachedAddr) String() string  { return "cache" }
@@ -0,0 +1,451 @@
+package cgminer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCached is fake API which counts received commands
+type fakeCached struct {
+	mu       sync.Mutex
+	commands []string
+
+	// gate blocks replies until closed, if set
+	gate chan struct{}
+}
+
+func (f *fakeCached) miner(address string, cache *ResponseCache) *CGMiner {
+	m := NewCGMiner(address, 4028, minerTimeout)
+	m.Cache = cache
+	m.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go f.serve(server)
+		return client, nil
+	})
+	return m
+}
+
+func (f *fakeCached) serve(conn net.Conn) {
+	defer conn.Close()
+	var cmd Command
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	f.commands = append(f.commands, cmd.Command)
+	gate := f.gate
+	f.mu.Unlock()
+	if gate != nil {
+		<-gate
+	}
+
+	status := `"STATUS":[{"STATUS":"S","Code":1}],"id":1`
+	var rsp string
+	switch cmd.Command {
+	case "summary":
+		rsp = `{` + status + `,"SUMMARY":[{"Elapsed":100,"MHS av":12.5}]}`
+	case "pools":
+		rsp = `{` + status + `,"POOLS":[{"POOL":0,"URL":"stratum+tcp://a:3333"}]}`
+	case "coin":
+		rsp = `{"STATUS":[{"STATUS":"E","Code":14,"Msg":"Invalid command"}],"id":1}`
+	default:
+		rsp = `{` + status + `}`
+	}
+	_, _ = conn.Write(append([]byte(rsp), 0x00))
+}
+
+func (f *fakeCached) count(command string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, c := range f.commands {
+		if c == command {
+			n++
+		}
+	}
+	return n
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	fake := &fakeCached{}
+	cache := NewResponseCache(50 * time.Millisecond)
+	cache.CommandTTL = map[string]time.Duration{"pools": 0}
+	miner := fake.miner(ip, cache)
+
+	for i := 0; i < 3; i++ {
+		summary, err := miner.Summary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.MHSav != 12.5 {
+			t.Errorf("unexpected summary: %+v", summary)
+		}
+	}
+	if n := fake.count("summary"); n != 1 {
+		t.Errorf("want single summary request, got %d", n)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := miner.Summary(); err != nil {
+		t.Fatal(err)
+	}
+	if n := fake.count("summary"); n != 2 {
+		t.Errorf("expired reply should be refetched, got %d requests", n)
+	}
+
+	// caching disabled by CommandTTL
+	for i := 0; i < 2; i++ {
+		if _, err := miner.Pools(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := fake.count("pools"); n != 2 {
+		t.Errorf("want 2 pools requests, got %d", n)
+	}
+
+	// error replies are not cached
+	for i := 0; i < 2; i++ {
+		if _, err := miner.Coin(); err == nil || !strings.Contains(err.Error(), "Invalid command") {
+			t.Errorf("expected API error, got %v", err)
+		}
+	}
+	if n := fake.count("coin"); n != 2 {
+		t.Errorf("want 2 coin requests, got %d", n)
+	}
+}
+
+func TestResponseCache_Singleflight(t *testing.T) {
+	fake := &fakeCached{gate: make(chan struct{})}
+	cache := NewResponseCache(time.Minute)
+	miner := fake.miner(ip, cache)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := miner.Summary(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Stats().Shared != callers-1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("callers didn't join in-flight request: %+v", cache.Stats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(fake.gate)
+	wg.Wait()
+
+	if n := fake.count("summary"); n != 1 {
+		t.Errorf("want single summary request, got %d", n)
+	}
+	if stats := cache.Stats(); stats.Misses != 1 || stats.Shared != callers-1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestResponseCache_CanceledLeader(t *testing.T) {
+	fake := &fakeCached{gate: make(chan struct{})}
+	cache := NewResponseCache(time.Minute)
+	miner := fake.miner(ip, cache)
+	miner.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		select {
+		case <-fake.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		client, server := net.Pipe()
+		go fake.serve(server)
+		return client, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := miner.SummaryContext(ctx)
+		leader <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Stats().Misses != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("leader request was not started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	follower := make(chan error, 1)
+	go func() {
+		_, err := miner.Summary()
+		follower <- err
+	}()
+	for cache.Stats().Shared != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("follower didn't join in-flight request")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-leader; err == nil {
+		t.Error("expected leader error")
+	}
+
+	// follower retries with own context
+	close(fake.gate)
+	if err := <-follower; err != nil {
+		t.Errorf("follower failed: %v", err)
+	}
+}
+
+func TestResponseCache_Invalidate(t *testing.T) {
+	fake := &fakeCached{}
+	cache := NewResponseCache(time.Minute)
+	miner := fake.miner(ip, cache)
+
+	// client with the same address shares replies, other miner doesn't
+	same := fake.miner(ip, cache)
+	other := fake.miner("10.0.0.2", cache)
+
+	read := func(m *CGMiner) {
+		t.Helper()
+		if _, err := m.Summary(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Pools(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	read(miner)
+	read(same)
+	read(other)
+	if s, p := fake.count("summary"), fake.count("pools"); s != 2 || p != 2 {
+		t.Fatalf("want 2 summary and pools requests, got %d and %d", s, p)
+	}
+
+	if err := miner.AddPool("stratum+tcp://b:3333", "user", "x"); err != nil {
+		t.Fatal(err)
+	}
+	read(same)
+	if s, p := fake.count("summary"), fake.count("pools"); s != 2 || p != 3 {
+		t.Errorf("addpool should invalidate only pools, got %d summary and %d pools requests", s, p)
+	}
+
+	if err := miner.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	read(miner)
+	read(other)
+	if s, p := fake.count("summary"), fake.count("pools"); s != 3 || p != 4 {
+		t.Errorf("restart should invalidate all miner replies, got %d summary and %d pools requests", s, p)
+	}
+
+	cache.Invalidate(other, "summary")
+	read(other)
+	if s, p := fake.count("summary"), fake.count("pools"); s != 4 || p != 4 {
+		t.Errorf("got %d summary and %d pools requests after Invalidate", s, p)
+	}
+
+	if stats := cache.Stats(); stats.Invalidations != 4 {
+		t.Errorf("want 4 invalidations, got %+v", stats)
+	}
+}
+
+func TestResponseCache_GPUReadback(t *testing.T) {
+	fake := &fakeGPU{dev: Devs{GPU: 1, Intensity: "20", GPUClock: 1000}}
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Dialer = fake.dialer()
+	miner.Cache = NewResponseCache(time.Minute)
+
+	if _, err := miner.GPU(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// readback must not see cached reply
+	if err := miner.SetGPUEngineClock(1, 1100); err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := miner.GPU(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.GPUClock != 1100 {
+		t.Errorf("want engine clock 1100, got %d", dev.GPUClock)
+	}
+}
+
+func TestResponseCache_GPUReadbackInFlight(t *testing.T) {
+	fake := &fakeGPU{dev: Devs{GPU: 1, Intensity: "20", GPUClock: 1000}}
+	var (
+		mu     sync.Mutex
+		dials  int
+		queued = make(chan struct{})
+		stale  = make(chan struct{})
+	)
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Cache = NewResponseCache(time.Minute)
+	miner.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		client, server := net.Pipe()
+		mu.Lock()
+		dials++
+		first := dials == 1
+		mu.Unlock()
+
+		if !first {
+			go func() {
+				mu.Lock()
+				defer mu.Unlock()
+				fake.serve(server)
+			}()
+			return client, nil
+		}
+
+		// first "gpu" request is answered with value before setting,
+		// after the setting is applied
+		go func() {
+			defer server.Close()
+			_ = json.NewDecoder(server).Decode(new(Command))
+			close(queued)
+			<-stale
+			rsp := `{"STATUS":[{"STATUS":"S","Code":1}],"GPU":[{"GPU":1,"GPU Clock":1000}],"id":1}`
+			_, _ = server.Write(append([]byte(rsp), 0x00))
+		}()
+		return client, nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := miner.GPU(1)
+		done <- err
+	}()
+	<-queued
+
+	// release in-flight request while readback might be waiting for it
+	time.AfterFunc(100*time.Millisecond, func() { close(stale) })
+	if err := miner.SetGPUEngineClock(1, 1100); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResponseCache_TransportKey(t *testing.T) {
+	var requests int32
+	dialer := DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		atomic.AddInt32(&requests, 1)
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			buf := make([]byte, 512)
+			n, _ := server.Read(buf)
+
+			// reply in request format
+			rsp := "STATUS=S,When=1650000000,Code=11,Msg=Summary|SUMMARY,Elapsed=100|"
+			if bytes.HasPrefix(buf[:n], []byte("{")) {
+				rsp = `{"STATUS":[{"STATUS":"S","Code":11}],"SUMMARY":[{"Elapsed":100}],"id":1}`
+			}
+			_, _ = server.Write(append([]byte(rsp), 0x00))
+		}()
+		return client, nil
+	})
+
+	cache := NewResponseCache(time.Minute)
+	jsonMiner := NewCGMiner(ip, 4028, minerTimeout)
+	jsonMiner.Dialer = dialer
+	jsonMiner.Cache = cache
+	textMiner := NewCGMiner(ip, 4028, minerTimeout)
+	textMiner.Dialer = dialer
+	textMiner.Cache = cache
+	textMiner.Transport = NewTextTransport()
+
+	for _, miner := range []*CGMiner{jsonMiner, textMiner, jsonMiner, textMiner} {
+		summary, err := miner.Summary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.Elapsed != 100 {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("want request per transport, got %d", n)
+	}
+	if stats := cache.Stats(); stats.Misses != 2 || stats.Hits != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestResponseCache_DecodeOnce(t *testing.T) {
+	var requests, repairs int32
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Cache = NewResponseCache(time.Minute)
+	miner.Transport = NewTolerantJSONTransport(func(Command, []string) {
+		atomic.AddInt32(&repairs, 1)
+	})
+	miner.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		atomic.AddInt32(&requests, 1)
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			_ = json.NewDecoder(server).Decode(new(Command))
+			rsp := `{"STATUS":[{"STATUS":"S","Code":11}],"SUMMARY":[{"Elapsed":100,}],"id":1}`
+			_, _ = server.Write(append([]byte(rsp), 0x00))
+		}()
+		return client, nil
+	})
+
+	// cacheability check doesn't go through transport
+	for i := 0; i < 3; i++ {
+		summary, err := miner.Summary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.Elapsed != 100 {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("want single summary request, got %d", n)
+	}
+	if n := atomic.LoadInt32(&repairs); n != 3 {
+		t.Errorf("want reply repaired once per call, got %d repairs", n)
+	}
+}
+
+func TestIsSuccessReply(t *testing.T) {
+	for raw, want := range map[string]bool{
+		`{"STATUS":[{"STATUS":"S","Code":11}],"SUMMARY":[{"Elapsed":100,}],"id":1}`: true,
+		`{"STATUS" : [{"STATUS":"I","Msg":"No pools"}],"id":1}`:                     true,
+		`{"STATUS":[{"STATUS":"E","Code":14,"Msg":"Invalid command"}],"id":1}`:      false,
+		`{"SUMMARY":[{"Elapsed":100}]}`:                                             false,
+		"STATUS=S,When=1650000000,Code=11,Msg=Summary|SUMMARY,Elapsed=100|":         true,
+		"STATUS=F,When=1650000000,Code=45,Msg=Access denied|":                       false,
+		"": false,
+	} {
+		if got := isSuccessReply([]byte(raw)); got != want {
+			t.Errorf("%q: want %t, got %t", raw, want, got)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package cgminer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -13,6 +16,67 @@ type Dialer interface {
 	Dial(network, address string) (net.Conn, error)
 }
 
+// ErrReadOnly is returned when write command is called on read-only client
+var ErrReadOnly = errors.New("cgminer: write commands are not allowed in read-only mode")
+
+// writeCommands is list of commands which change miner state, taken from
+// privileged commands of cgminer API-README:
+// https://github.com/ckolivas/cgminer/blob/master/API-README
+var writeCommands = map[string]struct{}{
+	"addpool":       {},
+	"removepool":    {},
+	"enablepool":    {},
+	"disablepool":   {},
+	"switchpool":    {},
+	"poolpriority":  {},
+	"poolquota":     {},
+	"gpuenable":     {},
+	"gpudisable":    {},
+	"gpurestart":    {},
+	"gpuintensity":  {},
+	"gpumem":        {},
+	"gpuengine":     {},
+	"gpufan":        {},
+	"gpuvddc":       {},
+	"gpupowertune":  {},
+	"pgaenable":     {},
+	"pgadisable":    {},
+	"pgaset":        {},
+	"pgaidentify":   {},
+	"ascenable":     {},
+	"ascdisable":    {},
+	"ascset":        {},
+	"ascidentify":   {},
+	"setconfig":     {},
+	"failover-only": {},
+	"zero":          {},
+	"debug":         {},
+	"hotplug":       {},
+	"lockstats":     {},
+	"save":          {},
+	"restart":       {},
+	"quit":          {},
+}
+
+// IsWriteCommand reports whether command changes miner state
+func IsWriteCommand(name string) bool {
+	_, ok := writeCommands[name]
+	return ok
+}
+
+// DialerFunc is an adapter to allow the use of ordinary functions as Dialer
+type DialerFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// DialContext implements Dialer
+func (fn DialerFunc) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return fn(ctx, network, address)
+}
+
+// Dial implements Dialer
+func (fn DialerFunc) Dial(network, address string) (net.Conn, error) {
+	return fn(context.Background(), network, address)
+}
+
 // ConnectError represents API connection error
 type ConnectError struct {
 	err error
@@ -35,7 +99,12 @@ func (err ConnectError) Unwrap() error {
 
 // CGMiner is cgminer API client
 type CGMiner struct {
-	// Address is API endpoint address (host:port)
+	// Network is API endpoint network name ("tcp", "unix", etc).
+	//
+	// "tcp" is used if empty.
+	Network string
+
+	// Address is API endpoint address (host:port or socket path)
 	Address string
 
 	// Timeout is request timeout
@@ -49,6 +118,38 @@ type CGMiner struct {
 	// CGMiner might have one of two API formats - JSON or plain text.
 	// JSON is default one.
 	Transport Transport
+
+	// ReadOnly forbids commands which change miner state.
+	//
+	// ErrReadOnly is returned for such commands.
+	ReadOnly bool
+
+	// Retries is number of additional attempts when connection to API failed.
+	//
+	// Command is retried only on ConnectError, as it wasn't sent to miner.
+	Retries int
+
+	// RetryDelay is delay between retry attempts
+	RetryDelay time.Duration
+
+	// MaxResponseSize is response size limit in bytes.
+	//
+	// DefaultMaxResponseSize is used if zero, negative value disables the limit.
+	// ErrResponseTooLarge is returned when response exceeds the limit.
+	MaxResponseSize int64
+
+	// GPULimits holds allowed ranges for GPU tuning commands.
+	//
+	// DefaultGPULimits is used if nil.
+	GPULimits *GPULimits
+
+	// Cache is optional cache of read command replies,
+	// see ResponseCache. RawCall bypasses cache.
+	Cache *ResponseCache
+
+	// Limiter is optional request rate limiter shared between clients,
+	// see Limiter. Replies served from Cache don't take budget.
+	Limiter *Limiter
 }
 
 // Call sends command to cgminer API and writes result to passed response output
@@ -65,9 +166,24 @@ func (c *CGMiner) Call(cmd Command, out AbstractResponse) error {
 //
 // If command doesn't returns any response, nil "out" value should be passed.
 func (c *CGMiner) CallContext(ctx context.Context, cmd Command, out AbstractResponse) error {
-	conn, err := c.Dialer.DialContext(ctx, "tcp", c.Address)
+	if err := c.checkCommand(cmd); err != nil {
+		return err
+	}
+	if c.Cache != nil {
+		return c.Cache.call(ctx, c, cmd, out)
+	}
+	return c.call(ctx, cmd, out)
+}
+
+// call sends command and decodes response
+func (c *CGMiner) call(ctx context.Context, cmd Command, out AbstractResponse) error {
+	if err := c.limit(ctx, cmd); err != nil {
+		return err
+	}
+
+	conn, err := c.dial(ctx)
 	if err != nil {
-		return ConnectError{err: err}
+		return err
 	}
 
 	defer conn.Close()
@@ -79,24 +195,98 @@ func (c *CGMiner) CallContext(ctx context.Context, cmd Command, out AbstractResp
 	return c.Transport.DecodeResponse(conn, cmd, out)
 }
 
-// RawCall sends command to CGMiner API and returns raw response as slice of bytes.
-//
-// Response error check should be performed manually.
-func (c *CGMiner) RawCall(ctx context.Context, cmd Command) ([]byte, error) {
-	conn, err := c.Dialer.DialContext(ctx, "tcp", c.Address)
+// fetch sends command and returns raw response
+func (c *CGMiner) fetch(ctx context.Context, cmd Command) ([]byte, error) {
+	if err := c.limit(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	conn, err := c.dial(ctx)
 	if err != nil {
-		return nil, ConnectError{err: err}
+		return nil, err
 	}
 
 	defer conn.Close()
 	_ = conn.SetDeadline(time.Now().Add(c.Timeout))
 	if err = c.Transport.SendCommand(conn, cmd); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to send cgminer command: %w", err)
 	}
 
 	return readWithNullTerminator(conn)
 }
 
+// RawCall sends command to CGMiner API and returns raw response as slice of bytes.
+//
+// Response error check should be performed manually.
+func (c *CGMiner) RawCall(ctx context.Context, cmd Command) ([]byte, error) {
+	if err := c.checkCommand(cmd); err != nil {
+		return nil, err
+	}
+	return c.fetch(ctx, cmd)
+}
+
+func (c *CGMiner) limitConn(conn net.Conn) net.Conn {
+	switch {
+	case c.MaxResponseSize < 0:
+		return conn
+	case c.MaxResponseSize == 0:
+		return &limitedConn{Conn: conn, n: DefaultMaxResponseSize}
+	default:
+		return &limitedConn{Conn: conn, n: c.MaxResponseSize}
+	}
+}
+
+// endpointID returns key which identifies miner endpoint
+func (c *CGMiner) endpointID() string {
+	network := c.Network
+	if network == "" {
+		network = "tcp"
+	}
+	return network + "://" + c.Address
+}
+
+// limit waits for Limiter budget if limiter is set
+func (c *CGMiner) limit(ctx context.Context, cmd Command) error {
+	if c.Limiter == nil {
+		return nil
+	}
+	return c.Limiter.Wait(ctx, c, cmd)
+}
+
+func (c *CGMiner) checkCommand(cmd Command) error {
+	if c.ReadOnly && IsWriteCommand(cmd.Command) {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// dial connects to API endpoint and retries failed attempts
+// according to retry policy.
+func (c *CGMiner) dial(ctx context.Context) (net.Conn, error) {
+	network := c.Network
+	if network == "" {
+		network = "tcp"
+	}
+
+	for attempt := 0; ; attempt++ {
+		conn, err := c.Dialer.DialContext(ctx, network, c.Address)
+		if err == nil {
+			return c.limitConn(conn), nil
+		}
+		if attempt >= c.Retries || ctx.Err() != nil {
+			return nil, ConnectError{err: err}
+		}
+
+		t := time.NewTimer(c.RetryDelay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ConnectError{err: err}
+		case <-t.C:
+		}
+	}
+}
+
 // NewCGMiner returns a CGMiner client with JSON API transport
 func NewCGMiner(hostname string, port int, timeout time.Duration) *CGMiner {
 	return &CGMiner{
@@ -108,3 +298,67 @@ func NewCGMiner(hostname string, port int, timeout time.Duration) *CGMiner {
 		},
 	}
 }
+
+// NewCGMinerWithAddress returns a CGMiner client with JSON API transport
+// for address in URL form.
+//
+// See ParseAddress for supported address formats.
+func NewCGMinerWithAddress(address string, timeout time.Duration) (*CGMiner, error) {
+	network, addr, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CGMiner{
+		Network:   network,
+		Address:   addr,
+		Timeout:   timeout,
+		Transport: NewJSONTransport(),
+		Dialer: &net.Dialer{
+			Timeout: timeout,
+		},
+	}, nil
+}
+
+// ParseAddress splits address in URL form into network name and address
+// suitable for Dialer.
+//
+// Supported formats:
+//
+//	host:port                  - TCP address
+//	tcp://host:port            - TCP address, "tcp4" and "tcp6" schemes are also supported
+//	unix:///run/trm.sock       - Unix socket path
+//	unix:relative/trm.sock     - relative Unix socket path
+//
+// Any other scheme is passed to Dialer as network name as-is.
+func ParseAddress(address string) (network, addr string, err error) {
+	i := strings.Index(address, "://")
+	if i == -1 {
+		if strings.HasPrefix(address, "unix:") {
+			return "unix", strings.TrimPrefix(address, "unix:"), nil
+		}
+		return "tcp", address, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	network = u.Scheme
+	switch network {
+	case "unix", "unixpacket":
+		addr = u.Path
+		if u.Host != "" {
+			// relative path, e.g. "unix://run/trm.sock"
+			addr = u.Host + u.Path
+		}
+	default:
+		addr = u.Host
+	}
+
+	if addr == "" {
+		return "", "", fmt.Errorf("invalid address %q: empty host or path", address)
+	}
+	return network, addr, nil
+}
@@ -1,11 +1,14 @@
 package cgminer
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net"
+	"os"
 	"path"
 	"testing"
 	"time"
@@ -343,6 +346,29 @@ func TestSummaryConnRefused(t *testing.T) {
 	}
 }
 
+func TestAddPoolCommand(t *testing.T) {
+	sent := make(chan Command, 1)
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			var cmd Command
+			_ = json.NewDecoder(server).Decode(&cmd)
+			sent <- cmd
+			handleConn(server, []byte(`{"STATUS":[{"STATUS":"S","Code":55,"Msg":"Added pool"}],"id":1}`+"\x00"))
+		}()
+		return client, nil
+	})
+
+	if err := miner.AddPool("stratum+tcp://pool:3333", "user", "x"); err != nil {
+		t.Fatal(err)
+	}
+	cmd := <-sent
+	if cmd.Command != "addpool" || cmd.Parameter != "stratum+tcp://pool:3333,user,x" {
+		t.Errorf("unexpected command: %+v", cmd)
+	}
+}
+
 func TestStats(t *testing.T) {
 	testCaseValue := getFixture("TestStatsS9.json")
 	expected := &GenericStats{
@@ -1194,3 +1220,253 @@ func TestStatsS7(t *testing.T) {
 	finish()
 	wait(1)
 }
+
+func TestParseAddress(t *testing.T) {
+	cases := map[string]struct {
+		network string
+		addr    string
+		err     bool
+	}{
+		"127.0.0.1:4028":          {network: "tcp", addr: "127.0.0.1:4028"},
+		"tcp://rig-1:4028":        {network: "tcp", addr: "rig-1:4028"},
+		"tcp6://[::1]:4028":       {network: "tcp6", addr: "[::1]:4028"},
+		"unix:///run/trm.sock":    {network: "unix", addr: "/run/trm.sock"},
+		"unix://run/trm.sock":     {network: "unix", addr: "run/trm.sock"},
+		"unix:run/trm.sock":       {network: "unix", addr: "run/trm.sock"},
+		"unixpacket:///tmp/s.sck": {network: "unixpacket", addr: "/tmp/s.sck"},
+		"tcp://":                  {err: true},
+		"tcp://%zz":               {err: true},
+	}
+
+	for input, c := range cases {
+		t.Run(input, func(t *testing.T) {
+			network, addr, err := ParseAddress(input)
+			if c.err {
+				if err == nil {
+					t.Fatalf("expected error, got %q %q", network, addr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if network != c.network || addr != c.addr {
+				t.Fatalf("got %q %q, want %q %q", network, addr, c.network, c.addr)
+			}
+		})
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgminer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sock := path.Join(dir, "trm.sock")
+	listener, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	payload := getFixture("TestVersion.json")
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		handleConn(conn, payload)
+	}()
+
+	miner, err := NewCGMinerWithAddress("unix://"+sock, minerTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, err := miner.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.Type != "Antminer S9" {
+		t.Fatalf("unexpected version response: %+v", version)
+	}
+}
+
+func TestDialerFunc_Pipe(t *testing.T) {
+	payload := getFixture("TestVersion.json")
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		if network != "tcp" || address != "127.0.0.1:4028" {
+			t.Errorf("unexpected dial address: %s %s", network, address)
+		}
+
+		client, server := net.Pipe()
+		go func() {
+			// consume command before reply
+			_, _ = bufio.NewReader(server).ReadBytes('\n')
+			handleConn(server, payload)
+		}()
+		return client, nil
+	})
+
+	version, err := miner.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.Type != "Antminer S9" {
+		t.Fatalf("unexpected version response: %+v", version)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	miner := NewCGMiner(ip, getPort(), minerTimeout)
+	miner.ReadOnly = true
+	miner.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		t.Fatal("write command should not be sent")
+		return nil, nil
+	})
+
+	if err := miner.AddPool("stratum+tcp://pool:3333", "user", "x"); err != ErrReadOnly {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	if err := miner.RemovePool(&Pool{Pool: 1}); err != ErrReadOnly {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	if err := miner.Restart(); err != ErrReadOnly {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	for _, cmd := range []string{"switchpool", "debug", "hotplug", "lockstats", "pgaidentify", "ascidentify"} {
+		if _, err := miner.RawCall(context.Background(), NewCommand(cmd, "1")); err != ErrReadOnly {
+			t.Fatalf("%s: expected ErrReadOnly, got %v", cmd, err)
+		}
+	}
+}
+
+func TestRetries(t *testing.T) {
+	payload := getFixture("TestVersion.json")
+	attempts := 0
+	miner := NewCGMiner(ip, getPort(), minerTimeout)
+	miner.Retries = 2
+	miner.Dialer = DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, fmt.Errorf("attempt %d failed", attempts)
+		}
+
+		client, server := net.Pipe()
+		go func() {
+			_, _ = bufio.NewReader(server).ReadBytes('\n')
+			handleConn(server, payload)
+		}()
+		return client, nil
+	})
+
+	if _, err := miner.Version(); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = -10
+	if _, err := miner.Version(); err == nil {
+		t.Fatal("expected ConnectError after all retries failed")
+	} else if _, ok := err.(ConnectError); !ok {
+		t.Fatalf("expected ConnectError, got %T", err)
+	}
+	if attempts != -7 {
+		t.Fatalf("expected 3 attempts, got %d", attempts+10)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	testCaseValue := getFixture("TestConfig.json")
+	expected := &Config{
+		GPUCount:    6,
+		PoolCount:   2,
+		Strategy:    "Failover",
+		LogInterval: 5,
+		DeviceCode:  "GPU ",
+		OS:          "Linux",
+		ScanTime:    60,
+		Queue:       1,
+		Expiry:      120,
+	}
+	ctx, finish := context.WithCancel(context.Background())
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, testCaseValue)
+	wait(1)
+	miner := NewCGMiner(ip, port, minerTimeout)
+	config, err := miner.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(config, expected); diff != nil {
+		t.Error(diff)
+	}
+	finish()
+	wait(1)
+}
+
+func TestCoin(t *testing.T) {
+	testCaseValue := getFixture("TestCoin.json")
+	expected := &Coin{
+		HashMethod:        "ethash",
+		CurrentBlockTime:  1649999990,
+		CurrentBlockHash:  "0x5f2a9c3b1d7e4a6f8b0c2d4e6f8a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a",
+		LP:                true,
+		NetworkDifficulty: 12345678901234,
+	}
+	ctx, finish := context.WithCancel(context.Background())
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, testCaseValue)
+	wait(1)
+	miner := NewCGMiner(ip, port, minerTimeout)
+	coin, err := miner.CoinContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(coin, expected); diff != nil {
+		t.Error(diff)
+	}
+	finish()
+	wait(1)
+}
+
+func TestNotify(t *testing.T) {
+	testCaseValue := getFixture("TestNotify.json")
+	expected := []Notify{
+		{
+			Notify:        0,
+			Name:          "GPU",
+			ID:            0,
+			LastWell:      1650000000,
+			ReasonNotWell: "None",
+		},
+		{
+			Notify:         1,
+			Name:           "GPU",
+			ID:             1,
+			LastWell:       1649990000,
+			LastNotWell:    1649990060,
+			ReasonNotWell:  "Device idle for 60s",
+			DevSickIdle60s: 2,
+			DevOverHeat:    1,
+		},
+	}
+	ctx, finish := context.WithCancel(context.Background())
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, testCaseValue)
+	wait(1)
+	miner := NewCGMiner(ip, port, minerTimeout)
+	notify, err := miner.Notify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(notify, expected); diff != nil {
+		t.Error(diff)
+	}
+	finish()
+	wait(1)
+}
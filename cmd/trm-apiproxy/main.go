@@ -0,0 +1,58 @@
+// Command trm-apiproxy serves cgminer API protocol in front of single miner.
+//
+// Usage:
+//
+//	trm-apiproxy -listen :4028 -upstream trm://10.0.0.10:4028
+//
+// Monitoring tools can poll proxy instead of miner, read commands
+// are answered from cache. Commands other than apiserver.ReadCommands
+// are denied unless -allow-write is set.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"github.com/sokdak/go-teamredminer-api/apiserver"
+)
+
+func main() {
+	listen := flag.String("listen", ":4028", "listen address")
+	upstream := flag.String("upstream", "", "miner endpoint, e.g. trm://10.0.0.10:4028?timeout=10s")
+	cacheTTL := flag.Duration("cache-ttl", 5*time.Second, "lifetime of cached responses, zero or negative to disable")
+	allowWrite := flag.Bool("allow-write", false, "forward all commands to miner, including write ones")
+	flag.Parse()
+
+	if err := run(*listen, *upstream, *cacheTTL, *allowWrite); err != nil {
+		fmt.Fprintln(os.Stderr, "trm-apiproxy:", err)
+		os.Exit(1)
+	}
+}
+
+func run(listen, upstream string, cacheTTL time.Duration, allowWrite bool) error {
+	if upstream == "" {
+		return errors.New("-upstream is required")
+	}
+
+	miner, err := cgminer.ParseEndpoint(upstream)
+	if err != nil {
+		return fmt.Errorf("upstream: %w", err)
+	}
+
+	if cacheTTL > 0 {
+		miner.Cache = cgminer.NewResponseCache(cacheTTL)
+	}
+
+	srv := apiserver.NewServer(miner)
+	if allowWrite {
+		srv.Forward = func(cgminer.Command) bool { return true }
+	}
+
+	log.Printf("trm-apiproxy: serving %s on %s", miner.Address, listen)
+	return srv.ListenAndServe(listen)
+}
@@ -0,0 +1,173 @@
+// Command trm-gateway serves REST/JSON API in front of miners.
+//
+// Usage:
+//
+//	trm-gateway -listen :8080 \
+//	    -miner rig1=trm://10.0.0.10:4028 -miner rig2=trm://10.0.0.11:4028?readonly=1 \
+//	    -token "$READ_TOKEN=read" -token "$ADMIN_TOKEN=read,write"
+//
+// Live miner snapshots are streamed at /stream as Server-Sent Events
+// or WebSocket, see package stream for query parameters. Browsers can pass
+// token as "access_token" query parameter or cookie there.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"github.com/sokdak/go-teamredminer-api/httpapi"
+	"github.com/sokdak/go-teamredminer-api/stream"
+)
+
+// listFlag is repeatable string flag
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *listFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+func main() {
+	var miners, tokens, groups, origins listFlag
+	listen := flag.String("listen", ":8080", "listen address")
+	cacheTTL := flag.Duration("cache-ttl", 5*time.Second, "lifetime of cached responses, zero or negative to disable")
+	timeout := flag.Duration("timeout", 10*time.Second, "miner call timeout")
+	pollInterval := flag.Duration("poll-interval", stream.DefaultPollInterval, "poll interval of /stream")
+	insecure := flag.Bool("insecure", false, "allow running without tokens (no authorization)")
+	flag.Var(&miners, "miner", "miner as id=endpoint, e.g. rig1=trm://10.0.0.10:4028 (repeatable)")
+	flag.Var(&groups, "group", "miner group for /stream as name=id[,id] (repeatable)")
+	flag.Var(&origins, "stream-origin", "origin allowed to open WebSocket /stream besides gateway host, e.g. https://dash.example.com (repeatable)")
+	flag.Var(&tokens, "token", "bearer token as token=scope[,scope], scopes are read and write (repeatable)")
+	flag.Parse()
+
+	cfg := config{
+		listen:       *listen,
+		miners:       miners,
+		tokens:       tokens,
+		groups:       groups,
+		origins:      origins,
+		cacheTTL:     *cacheTTL,
+		timeout:      *timeout,
+		pollInterval: *pollInterval,
+		insecure:     *insecure,
+	}
+	if err := run(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "trm-gateway:", err)
+		os.Exit(1)
+	}
+}
+
+type config struct {
+	listen            string
+	miners, tokens    []string
+	groups, origins   []string
+	cacheTTL, timeout time.Duration
+	pollInterval      time.Duration
+	insecure          bool
+}
+
+func run(cfg config) error {
+	if len(cfg.miners) == 0 {
+		return errors.New("at least one -miner is required")
+	}
+	if len(cfg.tokens) == 0 && !cfg.insecure {
+		return errors.New("no -token configured, use -insecure to disable authorization")
+	}
+
+	// cache is shared by REST API and stream poller
+	var cache *cgminer.ResponseCache
+	if cfg.cacheTTL > 0 {
+		cache = cgminer.NewResponseCache(cfg.cacheTTL)
+	}
+
+	m := make(map[string]*cgminer.CGMiner, len(cfg.miners))
+	for _, spec := range cfg.miners {
+		id, endpoint, ok := cut(spec)
+		if !ok || id == "" {
+			return fmt.Errorf("invalid -miner %q, expected id=endpoint", spec)
+		}
+
+		miner, err := cgminer.ParseEndpoint(endpoint)
+		if err != nil {
+			return fmt.Errorf("miner %s: %w", id, err)
+		}
+		miner.Cache = cache
+		m[id] = miner
+	}
+
+	srv := httpapi.NewServer(m)
+	srv.Timeout = cfg.timeout
+
+	if len(cfg.tokens) > 0 {
+		srv.Tokens = make(map[string][]httpapi.Scope, len(cfg.tokens))
+		for _, spec := range cfg.tokens {
+			token, scopes, ok := cutLast(spec)
+			if !ok || token == "" {
+				return errors.New("invalid -token, expected token=scope[,scope]")
+			}
+			for _, s := range strings.Split(scopes, ",") {
+				scope := httpapi.Scope(strings.TrimSpace(s))
+				if scope != httpapi.ReadScope && scope != httpapi.WriteScope {
+					return fmt.Errorf("invalid token scope %q", s)
+				}
+				srv.Tokens[token] = append(srv.Tokens[token], scope)
+			}
+		}
+	}
+
+	streams := &stream.Server{
+		Hub:            stream.NewHub(),
+		Groups:         make(map[string][]string),
+		AllowedOrigins: cfg.origins,
+	}
+	for _, spec := range cfg.groups {
+		name, ids, ok := cut(spec)
+		if !ok || name == "" {
+			return fmt.Errorf("invalid -group %q, expected name=id[,id]", spec)
+		}
+		for _, id := range strings.Split(ids, ",") {
+			if _, ok := m[id]; !ok {
+				return fmt.Errorf("group %s: unknown miner %q", name, id)
+			}
+			streams.Groups[name] = append(streams.Groups[name], id)
+		}
+	}
+
+	poller := stream.NewPoller(streams.Hub, m, cfg.pollInterval)
+	go poller.Run(context.Background())
+
+	mux := http.NewServeMux()
+	mux.Handle("/", srv)
+	mux.Handle("/stream", srv.RequireStreamScope(httpapi.ReadScope, streams))
+
+	log.Printf("trm-gateway: serving %d miners on %s", len(m), cfg.listen)
+	return http.ListenAndServe(cfg.listen, mux)
+}
+
+// cut slices s around the first "=", as miner endpoint might contain it
+func cut(s string) (before, after string, found bool) {
+	if i := strings.Index(s, "="); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	return s, "", false
+}
+
+// cutLast slices s around the last "=", as base64 token might end with it
+func cutLast(s string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, "="); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sokdak/go-teamredminer-api/export"
+	"github.com/sokdak/go-teamredminer-api/history"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dir := fs.String("dir", "", "history directory (required)")
+	since := fs.String("since", "24h", "start of range, as duration before now (e.g. 720h) or RFC 3339 time")
+	until := fs.String("until", "", "end of range, as duration before now or RFC 3339 time (default now)")
+	table := fs.String("table", "summary", "table to export: summary, devs or pools")
+	format := fs.String("format", "csv", "output format: csv or columnar")
+	out := fs.String("o", "", "output file (default stdout)")
+	// store layout options must match ones used by history writer
+	segment := fs.Duration("segment", time.Hour, "segment duration of history store")
+	compactWidth := fs.Duration("compact-width", 5*time.Minute, "bucket width of compacted history segments")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		return errors.New("export: -dir is required")
+	}
+
+	now := time.Now()
+	from, err := parseTime(*since, now)
+	if err != nil {
+		return fmt.Errorf("export: invalid -since: %w", err)
+	}
+
+	to := now
+	if *until != "" {
+		if to, err = parseTime(*until, now); err != nil {
+			return fmt.Errorf("export: invalid -until: %w", err)
+		}
+	}
+
+	write, err := writerFor(*format)
+	if err != nil {
+		return err
+	}
+
+	// OpenDisk creates missing directory, which is not wanted here
+	if _, err := os.Stat(*dir); err != nil {
+		return err
+	}
+
+	store, err := history.OpenDisk(*dir, history.DiskOptions{
+		SegmentDuration: *segment,
+		CompactWidth:    *compactWidth,
+	})
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	tables, err := export.ExportHistory(store, from, to)
+	if errors.Is(err, history.ErrCorrupted) {
+		// readable records are exported anyway
+		fmt.Fprintln(os.Stderr, "trmctl: warning:", err)
+	} else if err != nil {
+		return err
+	}
+
+	t, ok := tables[*table]
+	if !ok {
+		return fmt.Errorf("export: unknown table %q", *table)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := write(bw, t); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// parseTime parses RFC 3339 time or duration before now
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func writerFor(format string) (func(io.Writer, *export.Table) error, error) {
+	switch format {
+	case "csv":
+		return export.WriteCSV, nil
+	case "columnar":
+		return export.WriteColumnar, nil
+	default:
+		return nil, fmt.Errorf("export: unknown format %q", format)
+	}
+}
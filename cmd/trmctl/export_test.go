@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"github.com/sokdak/go-teamredminer-api/history"
+)
+
+func TestRunExport_Header(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trmctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := history.OpenDisk(filepath.Join(dir, "history"), history.DiskOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().Add(-time.Hour)
+	_ = store.AppendSummary("rig1", at, cgminer.Summary{Accepted: 10, Elapsed: 60})
+	_ = store.AppendDevs("rig1", at, []cgminer.Devs{{GPU: 0}})
+	_ = store.AppendPools("rig1", at, []cgminer.Pool{{Pool: 0}})
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// columns are json tags of response structs, limited to stored fields
+	expected := map[string]string{
+		"summary": "time,miner,Accepted,Difficulty Accepted,Difficulty Rejected,Elapsed,Hardware Errors,MHS 5s,MHS av,Rejected,Stale",
+		"devs": "time,miner,GPU,Temperature,Fan Percent,GPU Clock,Memory Clock,GPU Power,MHS av,MHS 5s,Accepted," +
+			"Rejected,Hardware Errors,Difficulty Accepted,Device Elapsed",
+		"pools": "time,miner,Accepted,Difficulty Accepted,Difficulty Rejected,POOL,Rejected,Stale",
+	}
+	for table, header := range expected {
+		out := filepath.Join(dir, table+".csv")
+		if err := runExport([]string{"-dir", filepath.Join(dir, "history"), "-since", "2h", "-table", table, "-o", out}); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := os.Open(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := bufio.NewReader(f)
+		line, _ := r.ReadString('\n')
+		rest, _ := ioutil.ReadAll(r)
+		f.Close()
+
+		if line != header+"\n" {
+			t.Errorf("%s: unexpected header:\n%s\nexpected:\n%s", table, line, header)
+		}
+		if len(rest) == 0 {
+			t.Errorf("%s: no rows exported", table)
+		}
+	}
+
+	if err := runExport([]string{"-dir", filepath.Join(dir, "history"), "-table", "gpu"}); err == nil {
+		t.Error("expected error for unknown table")
+	}
+}
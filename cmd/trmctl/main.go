@@ -0,0 +1,43 @@
+// Command trmctl is command line tool for miners managed by this module.
+//
+// Usage:
+//
+//	trmctl export --dir /var/lib/trm --since 720h --table summary --format csv > summary.csv
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: trmctl <command> [flags]
+
+Commands:
+  export    export stored poll history as CSV or columnar file
+
+Run "trmctl <command> -h" for command flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "trmctl:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,115 @@
+package cgminer
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidInterval is returned by Delta when current sample
+// is not newer than previous one.
+var ErrInvalidInterval = errors.New("current sample is not newer than previous")
+
+// hashesPerDiff1 is number of hashes needed on average to find difficulty 1 share
+const hashesPerDiff1 = 1 << 32
+
+// Sample is counters snapshot taken at specific time
+type Sample struct {
+	// Time is poll time
+	Time time.Time
+
+	// Elapsed is miner or device Elapsed counter in seconds,
+	// zero if unknown
+	Elapsed int64
+
+	Counters Counters
+}
+
+// SummarySample returns sample of Summary counters
+func SummarySample(t time.Time, s Summary) Sample {
+	return Sample{Time: t, Elapsed: s.Elapsed, Counters: SummaryCounters(s)}
+}
+
+// DevsSample returns sample of device counters
+func DevsSample(t time.Time, d Devs) Sample {
+	return Sample{Time: t, Elapsed: d.DeviceElapsed, Counters: DevsCounters(d)}
+}
+
+// PoolSample returns sample of pool counters.
+//
+// Pools have no Elapsed counter, so resets are detected by counters only.
+func PoolSample(t time.Time, p Pool) Sample {
+	return Sample{Time: t, Counters: PoolCounters(p)}
+}
+
+// Rates holds counter changes and rates over interval between two samples
+type Rates struct {
+	// Interval is interval covered by Delta
+	Interval time.Duration
+
+	// Reset is true if counters were reset between samples.
+	// Delta then covers only time since reset.
+	Reset bool
+
+	// Delta is counters change over interval
+	Delta Counters
+
+	// SharesPerMinute is accepted shares per minute
+	SharesPerMinute float64
+
+	// EffectiveHashrate is pool-side hashrate in H/s derived from
+	// accepted difficulty
+	EffectiveHashrate float64
+
+	// RejectRatio is rejected share of submitted work, by difficulty
+	// if available, otherwise by share count
+	RejectRatio float64
+
+	// HardwareErrorRatio is hardware errors share of all work
+	HardwareErrorRatio float64
+}
+
+// EffectiveMHS returns effective hashrate in MH/s, same unit as MHS fields
+func (r Rates) EffectiveMHS() float64 {
+	return r.EffectiveHashrate / 1e6
+}
+
+// Delta returns per-interval rates between two samples of the same source.
+//
+// Delta is reset-aware: when Elapsed counter went backwards or any counter
+// decreased, counters of current sample are treated as accumulated since
+// reset and interval is shortened to current Elapsed (if known).
+func Delta(prev, cur Sample) (Rates, error) {
+	interval := cur.Time.Sub(prev.Time)
+	if interval <= 0 {
+		return Rates{}, ErrInvalidInterval
+	}
+
+	r := Rates{Interval: interval, Delta: cur.Counters.Sub(prev.Counters)}
+	if cur.Elapsed < prev.Elapsed || cur.Counters.Decreased(prev.Counters) {
+		r.Reset = true
+		r.Delta = cur.Counters
+		if elapsed := time.Duration(cur.Elapsed) * time.Second; elapsed > 0 && elapsed < interval {
+			r.Interval = elapsed
+		}
+	}
+
+	minutes := r.Interval.Minutes()
+	d := r.Delta
+	r.SharesPerMinute = float64(d.Accepted) / minutes
+	r.EffectiveHashrate = d.DifficultyAccepted * hashesPerDiff1 / r.Interval.Seconds()
+
+	if total := d.DifficultyAccepted + d.DifficultyRejected; total > 0 {
+		r.RejectRatio = d.DifficultyRejected / total
+	} else if total := d.Accepted + d.Rejected; total > 0 {
+		r.RejectRatio = float64(d.Rejected) / float64(total)
+	}
+
+	work := d.Diff1Work
+	if work == 0 {
+		work = float64(d.Accepted + d.Rejected)
+	}
+	if total := work + float64(d.HardwareErrors); total > 0 {
+		r.HardwareErrorRatio = float64(d.HardwareErrors) / total
+	}
+	return r, nil
+}
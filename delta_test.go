@@ -0,0 +1,69 @@
+package cgminer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDelta(t *testing.T) {
+	now := time.Now()
+	prev := SummarySample(now, Summary{Elapsed: 1000, Accepted: 100, Rejected: 2, DifficultyAccepted: 1000, DifficultyRejected: 20, HardwareErrors: 1})
+	cur := SummarySample(now.Add(2*time.Minute), Summary{Elapsed: 1120, Accepted: 130, Rejected: 3, DifficultyAccepted: 1300, DifficultyRejected: 30, HardwareErrors: 1})
+
+	r, err := Delta(prev, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Reset || r.Interval != 2*time.Minute {
+		t.Fatalf("unexpected interval: %+v", r)
+	}
+	if r.SharesPerMinute != 15 {
+		t.Errorf("expected 15 shares/min, got %v", r.SharesPerMinute)
+	}
+	if want := 300.0 * (1 << 32) / 120; r.EffectiveHashrate != want {
+		t.Errorf("expected hashrate %v, got %v", want, r.EffectiveHashrate)
+	}
+	if want := 10.0 / 310; math.Abs(r.RejectRatio-want) > 1e-9 {
+		t.Errorf("expected reject ratio %v, got %v", want, r.RejectRatio)
+	}
+	if r.HardwareErrorRatio != 0 {
+		t.Errorf("expected no HW errors, got %v", r.HardwareErrorRatio)
+	}
+}
+
+func TestDelta_Reset(t *testing.T) {
+	now := time.Now()
+	prev := DevsSample(now, Devs{DeviceElapsed: 5000, Accepted: 500, Diff1Work: 5000, HardwareErrors: 10})
+	cur := DevsSample(now.Add(5*time.Minute), Devs{DeviceElapsed: 60, Accepted: 6, Diff1Work: 99, HardwareErrors: 1})
+
+	r, err := Delta(prev, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Reset || r.Interval != time.Minute {
+		t.Fatalf("expected reset with 1m interval, got %+v", r)
+	}
+	if r.Delta.Accepted != 6 || r.SharesPerMinute != 6 {
+		t.Errorf("unexpected rates after reset: %+v", r)
+	}
+	if r.HardwareErrorRatio != 0.01 {
+		t.Errorf("expected HW error ratio 0.01, got %v", r.HardwareErrorRatio)
+	}
+
+	// pools have no Elapsed, reset is detected by counters
+	prev = PoolSample(now, Pool{Accepted: 100, DifficultyAccepted: 100})
+	cur = PoolSample(now.Add(time.Minute), Pool{Accepted: 4, DifficultyAccepted: 4})
+	if r, _ = Delta(prev, cur); !r.Reset || r.Delta.Accepted != 4 || r.Interval != time.Minute {
+		t.Errorf("unexpected pool rates: %+v", r)
+	}
+}
+
+func TestDelta_InvalidInterval(t *testing.T) {
+	now := time.Now()
+	if _, err := Delta(Sample{Time: now}, Sample{Time: now}); err != ErrInvalidInterval {
+		t.Fatalf("expected ErrInvalidInterval, got %v", err)
+	}
+}
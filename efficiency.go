@@ -0,0 +1,121 @@
+package cgminer
+
+import (
+	"sort"
+	"time"
+)
+
+// PoolSnapshot is pools and devices state taken at specific time
+type PoolSnapshot struct {
+	Time  time.Time
+	Pools []Pool
+	Devs  []Devs
+}
+
+// PoolEfficiency compares pool-side effective hashrate with hashrate
+// reported by devices over interval between two snapshots.
+type PoolEfficiency struct {
+	Pool int64
+	URL  string
+	User string
+
+	// Rates is pool counters rates over interval
+	Rates Rates
+
+	// EffectiveHashrate is hashrate in H/s credited by pool,
+	// derived from accepted difficulty
+	EffectiveHashrate float64
+
+	// ReportedHashrate is part of devices hashrate (MHS av) in H/s
+	// attributed to pool by share of work sent to it
+	ReportedHashrate float64
+
+	// Ratio is EffectiveHashrate to ReportedHashrate ratio,
+	// zero if reported hashrate is unknown
+	Ratio float64
+
+	// StalePercent is stale share of submitted difficulty
+	StalePercent float64
+
+	// RejectPercent is rejected share of submitted difficulty
+	RejectPercent float64
+
+	// AvgShareInterval is average time between shares, derived
+	// from LastShareTime. Zero if no shares were submitted.
+	AvgShareInterval time.Duration
+}
+
+// PoolEfficiencies returns efficiency report for each pool present
+// in both snapshots.
+//
+// Devices hashrate is split between pools proportionally to diff1 work
+// sent to them (or to accepted shares if diff1 work is not reported),
+// so failover and load-balanced setups are handled alike.
+func PoolEfficiencies(prev, cur PoolSnapshot) ([]PoolEfficiency, error) {
+	prevPools := make(map[int64]Pool, len(prev.Pools))
+	for _, p := range prev.Pools {
+		prevPools[p.Pool] = p
+	}
+
+	var reported float64
+	for _, d := range cur.Devs {
+		reported += d.MHSav * 1e6
+	}
+
+	var (
+		report                   []PoolEfficiency
+		totalWork, totalAccepted float64
+	)
+	for _, p := range cur.Pools {
+		old, ok := prevPools[p.Pool]
+		if !ok || old.URL != p.URL {
+			continue
+		}
+
+		rates, err := Delta(PoolSample(prev.Time, old), PoolSample(cur.Time, p))
+		if err != nil {
+			return nil, err
+		}
+
+		e := PoolEfficiency{
+			Pool:              p.Pool,
+			URL:               p.URL,
+			User:              p.User,
+			Rates:             rates,
+			EffectiveHashrate: rates.EffectiveHashrate,
+			RejectPercent:     rates.RejectRatio * 100,
+		}
+
+		d := rates.Delta
+		if total := d.DifficultyAccepted + d.DifficultyRejected + d.DifficultyStale; total > 0 {
+			e.StalePercent = d.DifficultyStale / total * 100
+			e.RejectPercent = d.DifficultyRejected / total * 100
+		}
+
+		shares := d.Accepted + d.Rejected + d.Stale
+		if since := p.LastShareTime - old.LastShareTime; shares > 0 && since > 0 && !rates.Reset {
+			e.AvgShareInterval = time.Duration(since / float64(shares) * float64(time.Second))
+		}
+
+		totalWork += d.Diff1Work
+		totalAccepted += float64(d.Accepted)
+		report = append(report, e)
+	}
+
+	for i := range report {
+		d := report[i].Rates.Delta
+		switch {
+		case totalWork > 0:
+			report[i].ReportedHashrate = reported * d.Diff1Work / totalWork
+		case totalAccepted > 0:
+			report[i].ReportedHashrate = reported * float64(d.Accepted) / totalAccepted
+		}
+
+		if report[i].ReportedHashrate > 0 {
+			report[i].Ratio = report[i].EffectiveHashrate / report[i].ReportedHashrate
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool { return report[i].Pool < report[j].Pool })
+	return report, nil
+}
@@ -0,0 +1,64 @@
+package cgminer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPoolEfficiencies(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	prev := PoolSnapshot{
+		Time: now,
+		Pools: []Pool{
+			{Pool: 0, URL: "stratum+tcp://a:3333", Accepted: 100, DifficultyAccepted: 100, Diff1Shares: 100, LastShareTime: 1599999990},
+			{Pool: 1, URL: "stratum+tcp://b:3333", Accepted: 100, DifficultyAccepted: 100, Diff1Shares: 100, LastShareTime: 1599999990},
+			{Pool: 2, URL: "stratum+tcp://c:3333"},
+		},
+	}
+	cur := PoolSnapshot{
+		Time: now.Add(10 * time.Minute),
+		Pools: []Pool{
+			{Pool: 0, URL: "stratum+tcp://a:3333", Accepted: 160, Rejected: 5, Stale: 5, DifficultyAccepted: 160,
+				DifficultyRejected: 5, DifficultyStale: 5, Diff1Shares: 160, LastShareTime: 1600000590},
+			{Pool: 1, URL: "stratum+tcp://b:3333", Accepted: 120, DifficultyAccepted: 120, Diff1Shares: 160, LastShareTime: 1600000590},
+			{Pool: 3, URL: "stratum+tcp://d:3333", Accepted: 10},
+		},
+		Devs: []Devs{{MHSav: 1000}, {MHSav: 1000}},
+	}
+
+	report, err := PoolEfficiencies(prev, cur)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("expected 2 pools, got %+v", report)
+	}
+
+	a, b := report[0], report[1]
+	if a.URL != "stratum+tcp://a:3333" || b.Pool != 1 {
+		t.Fatalf("unexpected pools: %+v", report)
+	}
+
+	// both pools got same work, so reported hashrate is split evenly
+	if a.ReportedHashrate != 1e9 || b.ReportedHashrate != 1e9 {
+		t.Errorf("unexpected reported hashrate: %v, %v", a.ReportedHashrate, b.ReportedHashrate)
+	}
+
+	if want := 60.0 * (1 << 32) / 600; a.EffectiveHashrate != want {
+		t.Errorf("expected effective hashrate %v, got %v", want, a.EffectiveHashrate)
+	}
+	if math.Abs(b.Ratio-a.Ratio/3) > 1e-9 {
+		t.Errorf("pool b should be credited 3 times less: %v vs %v", b.Ratio, a.Ratio)
+	}
+
+	if math.Abs(a.StalePercent-100.0/14) > 1e-9 || math.Abs(a.RejectPercent-100.0/14) > 1e-9 {
+		t.Errorf("unexpected stale/reject: %v, %v", a.StalePercent, a.RejectPercent)
+	}
+	if a.AvgShareInterval != 600*time.Second/70 {
+		t.Errorf("unexpected share interval: %s", a.AvgShareInterval)
+	}
+	if b.AvgShareInterval != 30*time.Second {
+		t.Errorf("unexpected share interval: %s", b.AvgShareInterval)
+	}
+}
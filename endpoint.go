@@ -0,0 +1,153 @@
+package cgminer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPort is default cgminer API port
+	DefaultPort = 4028
+
+	// DefaultTimeout is request timeout used by ParseEndpoint when not specified
+	DefaultTimeout = 5 * time.Second
+)
+
+// ParseEndpoint returns CGMiner client configured from endpoint URL.
+//
+// Endpoint has following format:
+//
+//	trm://host[:port][?option=value&...]
+//
+// Address part is parsed by ParseAddress, but only networks supported
+// by net.Dialer are accepted: "tcp", "tcp4", "tcp6", "unix" and "unixpacket".
+// "trm" and "cgminer" schemes are aliases of "tcp". Port 4028 is used
+// if omitted for TCP addresses.
+//
+// Clients of custom networks should be created with NewCGMiner or as
+// CGMiner literal with own Dialer.
+//
+// Supported options:
+//
+//	timeout     - request timeout as Go duration (default: 5s)
+//	transport   - API format, "json" (default) or "text"
+//	repair      - repair malformed JSON responses (bool)
+//	readonly    - forbid commands which change miner state (bool)
+//	retry       - number of retry attempts on connection failure
+//	retry_delay - delay between retry attempts as Go duration
+//	tls         - wrap connection into TLS (bool)
+//	servername  - TLS server name override
+func ParseEndpoint(endpoint string) (*CGMiner, error) {
+	address, rawQuery := endpoint, ""
+	if i := strings.IndexByte(endpoint, '?'); i != -1 {
+		address, rawQuery = endpoint[:i], endpoint[i+1:]
+	}
+	for _, alias := range []string{"trm://", "cgminer://"} {
+		if strings.HasPrefix(address, alias) {
+			address = "tcp://" + strings.TrimPrefix(address, alias)
+		}
+	}
+
+	network, addr, err := ParseAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(strings.Trim(addr, "[]"), strconv.Itoa(DefaultPort))
+		}
+		if host, _, _ := net.SplitHostPort(addr); host == "" {
+			return nil, fmt.Errorf("invalid endpoint %q: empty host", endpoint)
+		}
+	case "unix", "unixpacket":
+	default:
+		// net.Dialer would fail on first call
+		return nil, fmt.Errorf("invalid endpoint %q: unsupported network %q", endpoint, network)
+	}
+
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+
+	c := &CGMiner{
+		Network:   network,
+		Address:   addr,
+		Timeout:   DefaultTimeout,
+		Transport: NewJSONTransport(),
+	}
+
+	var (
+		useTLS     bool
+		repair     bool
+		serverName string
+	)
+	for key, values := range q {
+		val := values[len(values)-1]
+		switch key {
+		case "timeout":
+			c.Timeout, err = parseDuration(val)
+		case "transport":
+			switch val {
+			case "json":
+				c.Transport = NewJSONTransport()
+			case "text":
+				c.Transport = NewTextTransport()
+			default:
+				err = fmt.Errorf("unknown transport %q", val)
+			}
+		case "repair":
+			repair, err = strconv.ParseBool(val)
+		case "readonly":
+			c.ReadOnly, err = strconv.ParseBool(val)
+		case "retry":
+			c.Retries, err = strconv.Atoi(val)
+			if err == nil && c.Retries < 0 {
+				err = errors.New("negative value")
+			}
+		case "retry_delay":
+			c.RetryDelay, err = parseDuration(val)
+		case "tls":
+			useTLS, err = strconv.ParseBool(val)
+		case "servername":
+			serverName = val
+		default:
+			err = errors.New("unknown option")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint option %q: %w", key, err)
+		}
+	}
+
+	if _, ok := c.Transport.(JSONTransport); ok && repair {
+		c.Transport = NewTolerantJSONTransport(nil)
+	}
+
+	c.Dialer = &net.Dialer{Timeout: c.Timeout}
+	if useTLS {
+		c.Dialer = &TLSDialer{
+			Dialer: c.Dialer,
+			Config: &tls.Config{ServerName: serverName},
+		}
+	}
+
+	return c, nil
+}
+
+// parseDuration parses non-negative duration
+func parseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err == nil && d < 0 {
+		return 0, errors.New("negative value")
+	}
+	return d, err
+}
@@ -0,0 +1,131 @@
+package cgminer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	cases := map[string]struct {
+		network    string
+		address    string
+		timeout    time.Duration
+		transport  Transport
+		readOnly   bool
+		retries    int
+		retryDelay time.Duration
+		tls        bool
+		err        string
+	}{
+		"trm://rig-12.site-a:4028?timeout=3s&transport=text&readonly=1": {
+			network:   "tcp",
+			address:   "rig-12.site-a:4028",
+			timeout:   3 * time.Second,
+			transport: TextTransport{},
+			readOnly:  true,
+		},
+		"trm://rig-12?retry=3&retry_delay=500ms": {
+			network:    "tcp",
+			address:    "rig-12:4028",
+			timeout:    DefaultTimeout,
+			transport:  JSONTransport{},
+			retries:    3,
+			retryDelay: 500 * time.Millisecond,
+		},
+		"10.0.0.5:4029": {
+			network:   "tcp",
+			address:   "10.0.0.5:4029",
+			timeout:   DefaultTimeout,
+			transport: JSONTransport{},
+		},
+		"cgminer://[fd00::1]?tls=true&servername=rig.local": {
+			network:   "tcp",
+			address:   "[fd00::1]:4028",
+			timeout:   DefaultTimeout,
+			transport: JSONTransport{},
+			tls:       true,
+		},
+		"unix:///run/trm.sock?timeout=1s": {
+			network:   "unix",
+			address:   "/run/trm.sock",
+			timeout:   time.Second,
+			transport: JSONTransport{},
+		},
+		"tcp6://[fd00::2]:4030": {
+			network:   "tcp6",
+			address:   "[fd00::2]:4030",
+			timeout:   DefaultTimeout,
+			transport: JSONTransport{},
+		},
+		"tcp4://10.0.0.5": {
+			network:   "tcp4",
+			address:   "10.0.0.5:4028",
+			timeout:   DefaultTimeout,
+			transport: JSONTransport{},
+		},
+		"rig-3?readonly=true": {
+			network:   "tcp",
+			address:   "rig-3:4028",
+			timeout:   DefaultTimeout,
+			transport: JSONTransport{},
+			readOnly:  true,
+		},
+		"unixpacket:///run/trm.sock": {
+			network:   "unixpacket",
+			address:   "/run/trm.sock",
+			timeout:   DefaultTimeout,
+			transport: JSONTransport{},
+		},
+		"trm://rig?transport=xml":   {err: "unknown transport"},
+		"trm://rig?timeout=3":       {err: `option "timeout"`},
+		"trm://rig?timeout=-1s":     {err: "negative value"},
+		"trm://rig?retry=-1":        {err: "negative value"},
+		"trm://rig?retry_delay=-1s": {err: "negative value"},
+		"trm://rig?foo=bar":         {err: "unknown option"},
+		"trm://:4028":               {err: "empty host"},
+		"unix://":                   {err: "empty host or path"},
+		"ssh://rig:4028":            {err: `unsupported network "ssh"`},
+		"udp://rig:4028":            {err: `unsupported network "udp"`},
+		"trm://rig?readonly=maybe":  {err: `option "readonly"`},
+	}
+
+	for input, c := range cases {
+		t.Run(input, func(t *testing.T) {
+			miner, err := ParseEndpoint(input)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("expected error containing %q, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if miner.Network != c.network || miner.Address != c.address {
+				t.Errorf("address mismatch: %s %s", miner.Network, miner.Address)
+			}
+			if miner.Timeout != c.timeout {
+				t.Errorf("timeout mismatch: %s", miner.Timeout)
+			}
+			if miner.Transport != c.transport {
+				t.Errorf("transport mismatch: %T", miner.Transport)
+			}
+			if miner.ReadOnly != c.readOnly {
+				t.Errorf("readonly mismatch: %t", miner.ReadOnly)
+			}
+			if miner.Retries != c.retries || miner.RetryDelay != c.retryDelay {
+				t.Errorf("retry mismatch: %d %s", miner.Retries, miner.RetryDelay)
+			}
+
+			tlsDialer, isTLS := miner.Dialer.(*TLSDialer)
+			if isTLS != c.tls {
+				t.Fatalf("dialer mismatch: %T", miner.Dialer)
+			}
+			if isTLS && tlsDialer.Config.ServerName != "rig.local" {
+				t.Errorf("server name mismatch: %q", tlsDialer.Config.ServerName)
+			}
+		})
+	}
+}
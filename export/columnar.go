@@ -0,0 +1,215 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// columnarMagic starts columnar file
+const columnarMagic = "TRMCOL1\n"
+
+// ErrBadColumnar is returned when columnar file is malformed
+var ErrBadColumnar = errors.New("malformed columnar file")
+
+// columnarHeader is JSON-encoded header of columnar file
+type columnarHeader struct {
+	Name    string           `json:"name"`
+	Rows    int              `json:"rows"`
+	Columns []columnarColumn `json:"columns"`
+}
+
+type columnarColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// WriteColumnar writes table in columnar format.
+//
+// File consists of magic string, length-prefixed JSON header with schema
+// and row count, and one block per column with values stored contiguously:
+// int64, float64 and times (Unix nanoseconds) as 8 bytes, bools as 1 byte,
+// strings prefixed by uint32 length. All integers are little-endian.
+// Each column block is prefixed by its uint64 length, so readers can
+// skip columns they don't need.
+func WriteColumnar(w io.Writer, t *Table) error {
+	header := columnarHeader{Name: t.Name, Rows: len(t.Rows)}
+	for _, c := range t.Schema {
+		header.Columns = append(header.Columns, columnarColumn{Name: c.Name, Type: c.Type.String()})
+	}
+
+	hdr, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(columnarMagic)
+	writeUint32(bw, uint32(len(hdr)))
+	bw.Write(hdr)
+
+	var block bytes.Buffer
+	for i, c := range t.Schema {
+		block.Reset()
+		for _, row := range t.Rows {
+			encodeValue(&block, c.Type, row[i])
+		}
+		writeUint64(bw, uint64(block.Len()))
+		bw.Write(block.Bytes())
+	}
+	return bw.Flush()
+}
+
+func encodeValue(buf *bytes.Buffer, typ ColumnType, v interface{}) {
+	switch typ {
+	case IntColumn:
+		writeUint64(buf, uint64(v.(int64)))
+	case FloatColumn:
+		writeUint64(buf, math.Float64bits(v.(float64)))
+	case TimeColumn:
+		writeUint64(buf, uint64(v.(time.Time).UnixNano()))
+	case BoolColumn:
+		if v.(bool) {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case StringColumn:
+		s := v.(string)
+		writeUint32(buf, uint32(len(s)))
+		buf.WriteString(s)
+	}
+}
+
+func writeUint32(w io.Writer, v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	_, _ = w.Write(b[:])
+}
+
+func writeUint64(w io.Writer, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	_, _ = w.Write(b[:])
+}
+
+// ReadColumnar reads table written by WriteColumnar
+func ReadColumnar(r io.Reader) (*Table, error) {
+	magic := make([]byte, len(columnarMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != columnarMagic {
+		return nil, fmt.Errorf("%w: bad magic", ErrBadColumnar)
+	}
+
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadColumnar, err)
+	}
+
+	hdr, err := readN(r, uint64(size))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadColumnar, err)
+	}
+
+	var header columnarHeader
+	if err := json.Unmarshal(hdr, &header); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadColumnar, err)
+	}
+	if header.Rows < 0 || (header.Rows > 0 && len(header.Columns) == 0) {
+		return nil, fmt.Errorf("%w: bad rows count %d", ErrBadColumnar, header.Rows)
+	}
+
+	t := &Table{Name: header.Name}
+	for col, c := range header.Columns {
+		typ, err := parseColumnType(c.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrBadColumnar, err)
+		}
+		t.Schema = append(t.Schema, Column{Name: c.Name, Type: typ})
+
+		var blockSize uint64
+		if err := binary.Read(r, binary.LittleEndian, &blockSize); err != nil {
+			return nil, fmt.Errorf("%w: column %q: %s", ErrBadColumnar, c.Name, err)
+		}
+
+		// rows count is checked before rows are allocated
+		if uint64(header.Rows) > blockSize/minValueSize(typ) {
+			return nil, fmt.Errorf("%w: column %q: %d rows don't fit into %d bytes", ErrBadColumnar, c.Name, header.Rows, blockSize)
+		}
+
+		data, err := readN(r, blockSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: column %q: %s", ErrBadColumnar, c.Name, err)
+		}
+		block := bytes.NewReader(data)
+
+		if t.Rows == nil {
+			t.Rows = make([][]interface{}, header.Rows)
+			for i := range t.Rows {
+				t.Rows[i] = make([]interface{}, len(header.Columns))
+			}
+		}
+
+		for row := range t.Rows {
+			v, err := decodeValue(block, typ)
+			if err != nil {
+				return nil, fmt.Errorf("%w: column %q row %d: %s", ErrBadColumnar, c.Name, row, err)
+			}
+			t.Rows[row][col] = v
+		}
+	}
+	return t, nil
+}
+
+func readN(r io.Reader, n uint64) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(n)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// minValueSize returns minimal encoded size of column value
+func minValueSize(typ ColumnType) uint64 {
+	switch typ {
+	case BoolColumn:
+		return 1
+	case StringColumn:
+		return 4
+	default:
+		return 8
+	}
+}
+
+func decodeValue(r *bytes.Reader, typ ColumnType) (interface{}, error) {
+	switch typ {
+	case BoolColumn:
+		b, err := r.ReadByte()
+		return b == 1, err
+	case StringColumn:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		s, err := readN(r, uint64(n))
+		return string(s), err
+	}
+
+	var v uint64
+	if err := binary.Read(r, binary.LittleEndian, &v); err != nil {
+		return nil, err
+	}
+	switch typ {
+	case IntColumn:
+		return int64(v), nil
+	case FloatColumn:
+		return math.Float64frombits(v), nil
+	default:
+		return time.Unix(0, int64(v)), nil
+	}
+}
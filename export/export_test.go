@@ -0,0 +1,187 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"github.com/sokdak/go-teamredminer-api/history"
+)
+
+func TestSchemaOf(t *testing.T) {
+	schema := SchemaOf(cgminer.Pool{})
+	names := schema.Names()
+
+	expected := []string{"Accepted", "Best Share", "Diff1 Shares", "Difficulty Accepted"}
+	if !reflect.DeepEqual(names[:4], expected) {
+		t.Fatalf("unexpected columns: %v", names)
+	}
+
+	types := map[string]ColumnType{}
+	for _, c := range schema {
+		types[c.Name] = c.Type
+	}
+	if types["Accepted"] != IntColumn || types["Best Share"] != FloatColumn ||
+		types["URL"] != StringColumn || types["Has Stratum"] != BoolColumn {
+		t.Fatalf("unexpected types: %v", types)
+	}
+
+	// Devs has two fields with "Accepted" json name
+	count := 0
+	for _, name := range SchemaOf(cgminer.Devs{}).Names() {
+		if name == "Accepted" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Fatalf("expected single Accepted column, got %d", count)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	table := NewTable("test", Schema{TimeCol, MinerCol, {Name: "Difficulty Accepted", Type: FloatColumn}})
+	if err := table.Append(time.Unix(0, 0), "rig,1", 1.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Append(time.Unix(0, 0), "rig", "bad"); err == nil {
+		t.Fatal("expected type error")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "time,miner,Difficulty Accepted\n1970-01-01T00:00:00Z,\"rig,1\",1.5\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestColumnar_RoundTrip(t *testing.T) {
+	at := time.Unix(1600000000, 0)
+	table := PoolsTable()
+	err := table.AppendPools(at, "rig1", []cgminer.Pool{
+		{Pool: 0, URL: "stratum+tcp://a:3333", Accepted: 10, DifficultyAccepted: 1.5, HasStratum: true},
+		{Pool: 1, URL: "stratum+tcp://b:3333", Accepted: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteColumnar(&buf, table); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadColumnar(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Name != "pools" || !reflect.DeepEqual(got.Schema.Names(), table.Schema.Names()) || len(got.Rows) != 2 {
+		t.Fatalf("unexpected table: %s %v", got.Name, got.Schema.Names())
+	}
+	for i, row := range got.Rows {
+		for j, v := range row {
+			if tv, ok := v.(time.Time); ok {
+				if !tv.Equal(table.Rows[i][j].(time.Time)) {
+					t.Errorf("row %d column %s: expected %v, got %v", i, got.Schema[j].Name, table.Rows[i][j], v)
+				}
+				continue
+			}
+			if v != table.Rows[i][j] {
+				t.Errorf("row %d column %s: expected %v, got %v", i, got.Schema[j].Name, table.Rows[i][j], v)
+			}
+		}
+	}
+
+	if _, err := ReadColumnar(strings.NewReader("garbage")); err == nil {
+		t.Fatal("expected error for bad input")
+	}
+
+	// untrusted rows count
+	for _, hdr := range []string{
+		`{"name":"t","rows":-1,"columns":[{"name":"a","type":"int"}]}`,
+		`{"name":"t","rows":1000000000,"columns":[{"name":"a","type":"int"}]}`,
+	} {
+		var bad bytes.Buffer
+		bad.WriteString(columnarMagic)
+		writeUint32(&bad, uint32(len(hdr)))
+		bad.WriteString(hdr)
+		writeUint64(&bad, 8)
+		writeUint64(&bad, 1)
+		if _, err := ReadColumnar(&bad); !errors.Is(err, ErrBadColumnar) {
+			t.Errorf("%s: expected ErrBadColumnar, got %v", hdr, err)
+		}
+	}
+}
+
+func TestExportHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := history.OpenDisk(dir, history.DiskOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	at := time.Unix(1600000000, 0)
+	_ = store.AppendSummary("rig1", at, cgminer.Summary{DifficultyAccepted: 100, Elapsed: 60})
+	_ = store.AppendDevs("rig1", at, []cgminer.Devs{{GPU: 0}, {GPU: 1}})
+	_ = store.Append("rig2", history.Record{Time: at, Source: history.SummarySource, Values: map[string]float64{history.Elapsed: 5}})
+
+	tables, err := ExportHistory(store, at, at.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := tables["summary"]
+	if len(summary.Rows) != 2 || len(tables["devs"].Rows) != 2 || len(tables["pools"].Rows) != 0 {
+		t.Fatalf("unexpected row counts: %d, %d", len(summary.Rows), len(tables["devs"].Rows))
+	}
+
+	col := map[string]int{}
+	for i, name := range summary.Schema.Names() {
+		col[name] = i
+	}
+	row := summary.Rows[0]
+	if row[col["miner"]] != "rig1" || row[col["Difficulty Accepted"]] != 100.0 || row[col["Elapsed"]] != int64(60) {
+		t.Fatalf("unexpected row: %v", row)
+	}
+	if v := summary.Rows[1][col["Difficulty Accepted"]].(float64); !math.IsNaN(v) {
+		t.Fatalf("missing value should be NaN, got %v", v)
+	}
+	if gpu := tables["devs"].Rows[1][2]; tables["devs"].Schema[2].Name != "GPU" || gpu != int64(1) {
+		t.Fatalf("unexpected device row: %v", tables["devs"].Rows[1])
+	}
+
+	// records of other miners are exported despite corruption
+	segments, _ := filepath.Glob(filepath.Join(dir, "rig2", "*.seg"))
+	if len(segments) != 1 {
+		t.Fatalf("expected single rig2 segment, got %v", segments)
+	}
+	data, _ := ioutil.ReadFile(segments[0])
+	data[len(data)-1] ^= 0xff
+	_ = ioutil.WriteFile(segments[0], data, 0644)
+
+	tables, err = ExportHistory(store, at, at.Add(time.Minute))
+	if !errors.Is(err, history.ErrCorrupted) {
+		t.Fatalf("expected ErrCorrupted, got %v", err)
+	}
+	if len(tables["summary"].Rows) != 1 || len(tables["devs"].Rows) != 2 {
+		t.Fatalf("unexpected row counts: %d, %d", len(tables["summary"].Rows), len(tables["devs"].Rows))
+	}
+}
@@ -0,0 +1,190 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sokdak/go-teamredminer-api/history"
+)
+
+// historyFields maps table columns to history fields they are stored as.
+// Other columns of response structs are not kept by history.
+var historyFields = map[string]map[string]string{
+	"summary": {
+		"Accepted":            history.Accepted,
+		"Rejected":            history.Rejected,
+		"Stale":               history.Stale,
+		"Hardware Errors":     history.HardwareErrors,
+		"Difficulty Accepted": history.DifficultyAccepted,
+		"Difficulty Rejected": history.DifficultyRejected,
+		"Elapsed":             history.Elapsed,
+		"MHS 5s":              history.MHS5s,
+		"MHS av":              history.MHSav,
+	},
+	"devs": {
+		"Accepted":            history.Accepted,
+		"Rejected":            history.Rejected,
+		"Hardware Errors":     history.HardwareErrors,
+		"Difficulty Accepted": history.DifficultyAccepted,
+		"Temperature":         history.Temperature,
+		"Fan Percent":         history.FanPercent,
+		"GPU Clock":           history.GPUClock,
+		"Memory Clock":        history.MemoryClock,
+		"GPU Power":           history.Power,
+		"Device Elapsed":      history.Elapsed,
+		"MHS 5s":              history.MHS5s,
+		"MHS av":              history.MHSav,
+	},
+	"pools": {
+		"Accepted":            history.Accepted,
+		"Rejected":            history.Rejected,
+		"Stale":               history.Stale,
+		"Difficulty Accepted": history.DifficultyAccepted,
+		"Difficulty Rejected": history.DifficultyRejected,
+	},
+}
+
+// indexColumns maps table to column filled with device or pool index
+// taken from history source, e.g. 0 for "gpu/0"
+var indexColumns = map[string]string{
+	"devs":  "GPU",
+	"pools": "POOL",
+}
+
+// sourceTables maps history source kind to table name
+var sourceTables = map[string]string{
+	history.SummarySource: "summary",
+	"gpu":                 "devs",
+	"pool":                "pools",
+}
+
+// HistoryTable returns empty table for history records, name is one of
+// "summary", "devs" or "pools". Schema is the one of SummaryTable, DevsTable
+// or PoolsTable, limited to columns which are stored in history.
+func HistoryTable(name string) (*Table, error) {
+	var base *Table
+	switch name {
+	case "summary":
+		base = SummaryTable()
+	case "devs":
+		base = DevsTable()
+	case "pools":
+		base = PoolsTable()
+	default:
+		return nil, fmt.Errorf("unknown table %q", name)
+	}
+
+	fields := historyFields[name]
+	var schema Schema
+	for _, c := range base.Schema {
+		_, stored := fields[c.Name]
+		if c.index == nil || stored || c.Name == indexColumns[name] {
+			schema = append(schema, c)
+		}
+	}
+	return NewTable(name, schema), nil
+}
+
+// AppendRecord adds history record row to table created by HistoryTable.
+//
+// Missing float values are stored as NaN, missing int values as zero.
+// Int values of downsampled records are rounded averages.
+func (t *Table) AppendRecord(miner string, rec history.Record) error {
+	fields, ok := historyFields[t.Name]
+	if !ok {
+		return fmt.Errorf("table %q is not a history table", t.Name)
+	}
+
+	row := make([]interface{}, len(t.Schema))
+	for i, c := range t.Schema {
+		switch {
+		case c.index == nil && c.Name == TimeCol.Name:
+			row[i] = rec.Time
+		case c.index == nil && c.Name == MinerCol.Name:
+			row[i] = miner
+		case c.Name == indexColumns[t.Name]:
+			row[i] = sourceIndex(rec.Source)
+		default:
+			v, ok := rec.Values[fields[c.Name]]
+			if !ok {
+				v = math.NaN()
+			}
+			row[i] = columnValue(c.Type, v)
+		}
+	}
+	return t.Append(row...)
+}
+
+func columnValue(typ ColumnType, v float64) interface{} {
+	if typ != IntColumn {
+		return v
+	}
+	if math.IsNaN(v) {
+		return int64(0)
+	}
+	return int64(math.Round(v))
+}
+
+// ExportHistory reads records of all miners stored in DiskStore within
+// [from, to) and returns tables created by HistoryTable keyed by name.
+//
+// Corrupted records are skipped, in this case tables are returned along
+// with error wrapping history.ErrCorrupted.
+func ExportHistory(store *history.DiskStore, from, to time.Time) (map[string]*Table, error) {
+	tables := make(map[string]*Table, len(sourceTables))
+	for _, name := range sourceTables {
+		t, err := HistoryTable(name)
+		if err != nil {
+			return nil, err
+		}
+		tables[name] = t
+	}
+
+	miners, err := store.Miners()
+	if err != nil {
+		return nil, err
+	}
+
+	var corrupted error
+	for _, miner := range miners {
+		records, err := store.ReadRange(miner, from, to)
+		switch {
+		case err == nil:
+		case errors.Is(err, history.ErrCorrupted):
+			if corrupted == nil {
+				corrupted = fmt.Errorf("%s: %w", miner, err)
+			}
+		default:
+			return nil, err
+		}
+
+		for _, rec := range records {
+			t, ok := tables[sourceTables[sourceKind(rec.Source)]]
+			if !ok {
+				continue
+			}
+			if err := t.AppendRecord(miner, rec); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tables, corrupted
+}
+
+// sourceKind returns source kind, e.g. "gpu" for "gpu/0"
+func sourceKind(source string) string {
+	if i := strings.IndexByte(source, '/'); i >= 0 {
+		return source[:i]
+	}
+	return source
+}
+
+// sourceIndex returns device or pool index of source, e.g. 0 for "gpu/0"
+func sourceIndex(source string) int64 {
+	i, _ := strconv.ParseInt(source[strings.IndexByte(source, '/')+1:], 10, 64)
+	return i
+}
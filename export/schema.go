@@ -0,0 +1,259 @@
+// Package export flattens miner responses into tables with stable
+// column schemas and writes them as CSV or columnar files.
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// ColumnType is column data type
+type ColumnType int
+
+// Column types
+const (
+	IntColumn ColumnType = iota + 1
+	FloatColumn
+	StringColumn
+	BoolColumn
+	TimeColumn
+)
+
+// String implements fmt.Stringer
+func (t ColumnType) String() string {
+	switch t {
+	case IntColumn:
+		return "int"
+	case FloatColumn:
+		return "float"
+	case StringColumn:
+		return "string"
+	case BoolColumn:
+		return "bool"
+	case TimeColumn:
+		return "time"
+	default:
+		return fmt.Sprintf("ColumnType(%d)", int(t))
+	}
+}
+
+// parseColumnType is reverse of ColumnType.String
+func parseColumnType(s string) (ColumnType, error) {
+	for t := IntColumn; t <= TimeColumn; t++ {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown column type %q", s)
+}
+
+// Column describes table column
+type Column struct {
+	Name string
+	Type ColumnType
+
+	// index is struct field index, nil for columns not backed by struct field
+	index []int
+}
+
+// Schema is ordered list of columns
+type Schema []Column
+
+// Names returns column names
+func (s Schema) Names() []string {
+	names := make([]string, len(s))
+	for i, c := range s {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Standard columns prepended to each struct schema
+var (
+	TimeCol  = Column{Name: "time", Type: TimeColumn}
+	MinerCol = Column{Name: "miner", Type: StringColumn}
+)
+
+// SchemaOf returns schema of struct fields.
+//
+// Column names are taken from json tags (field name if tag is absent),
+// column types are derived from field types. Fields of unsupported types
+// and fields which repeat already seen column name are skipped, so schema
+// is stable as long as struct is not changed.
+func SchemaOf(v interface{}) Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	seen := make(map[string]bool)
+	var schema Schema
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		typ, ok := columnType(f.Type)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		schema = append(schema, Column{Name: name, Type: typ, index: f.Index})
+	}
+	return schema
+}
+
+func columnType(t reflect.Type) (ColumnType, bool) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return IntColumn, true
+	case reflect.Float32, reflect.Float64:
+		return FloatColumn, true
+	case reflect.String:
+		return StringColumn, true
+	case reflect.Bool:
+		return BoolColumn, true
+	default:
+		return 0, false
+	}
+}
+
+// Table is set of rows sharing the same schema.
+//
+// Row values are int64, float64, string, bool or time.Time,
+// according to column type.
+type Table struct {
+	Name   string
+	Schema Schema
+	Rows   [][]interface{}
+}
+
+// NewTable returns empty table
+func NewTable(name string, schema Schema) *Table {
+	return &Table{Name: name, Schema: schema}
+}
+
+// SummaryTable returns empty table of Summary rows
+func SummaryTable() *Table {
+	return NewTable("summary", append(Schema{TimeCol, MinerCol}, SchemaOf(cgminer.Summary{})...))
+}
+
+// DevsTable returns empty table of device rows
+func DevsTable() *Table {
+	return NewTable("devs", append(Schema{TimeCol, MinerCol}, SchemaOf(cgminer.Devs{})...))
+}
+
+// PoolsTable returns empty table of pool rows
+func PoolsTable() *Table {
+	return NewTable("pools", append(Schema{TimeCol, MinerCol}, SchemaOf(cgminer.Pool{})...))
+}
+
+// Append adds row. Row length and value types must match schema.
+func (t *Table) Append(row ...interface{}) error {
+	if len(row) != len(t.Schema) {
+		return fmt.Errorf("row has %d values, schema has %d columns", len(row), len(t.Schema))
+	}
+
+	for i, v := range row {
+		if !valueFits(t.Schema[i].Type, v) {
+			return fmt.Errorf("column %q: unexpected value type %T", t.Schema[i].Name, v)
+		}
+	}
+	t.Rows = append(t.Rows, row)
+	return nil
+}
+
+func valueFits(typ ColumnType, v interface{}) bool {
+	switch v.(type) {
+	case int64:
+		return typ == IntColumn
+	case float64:
+		return typ == FloatColumn
+	case string:
+		return typ == StringColumn
+	case bool:
+		return typ == BoolColumn
+	case time.Time:
+		return typ == TimeColumn
+	default:
+		return false
+	}
+}
+
+// AppendStruct adds row from struct, time and miner columns are filled
+// with passed values. Struct type must match one used to build schema.
+func (t *Table) AppendStruct(at time.Time, miner string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	row := make([]interface{}, len(t.Schema))
+	for i, c := range t.Schema {
+		switch {
+		case c.Name == TimeCol.Name && c.index == nil:
+			row[i] = at
+		case c.Name == MinerCol.Name && c.index == nil:
+			row[i] = miner
+		case c.index != nil:
+			row[i] = structValue(rv.FieldByIndex(c.index))
+		default:
+			return fmt.Errorf("column %q has no value in %s", c.Name, rv.Type())
+		}
+	}
+	return t.Append(row...)
+}
+
+func structValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	default:
+		return v.String()
+	}
+}
+
+// AppendSummary adds Summary row
+func (t *Table) AppendSummary(at time.Time, miner string, s cgminer.Summary) error {
+	return t.AppendStruct(at, miner, s)
+}
+
+// AppendDevs adds row per device
+func (t *Table) AppendDevs(at time.Time, miner string, devs []cgminer.Devs) error {
+	for _, d := range devs {
+		if err := t.AppendStruct(at, miner, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendPools adds row per pool
+func (t *Table) AppendPools(at time.Time, miner string, pools []cgminer.Pool) error {
+	for _, p := range pools {
+		if err := t.AppendStruct(at, miner, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
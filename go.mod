@@ -5,4 +5,7 @@ go 1.14
 require (
 	github.com/go-test/deep v1.0.1
 	github.com/phayes/freeport v0.0.0-20180830031419-95f893ade6f2
+	golang.org/x/crypto v0.0.0-20220411220226-7b82a4e95df4
+	google.golang.org/grpc v1.41.0
+	google.golang.org/protobuf v1.27.1
 )
@@ -0,0 +1,211 @@
+package cgminer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ErrOutOfRange is returned when GPU setting value is outside of allowed range
+var ErrOutOfRange = errors.New("value is out of range")
+
+// Range is inclusive range of allowed values
+type Range struct {
+	Min float64
+	Max float64
+}
+
+// Contains reports whether value is within range
+func (r Range) Contains(v float64) bool {
+	return v >= r.Min && v <= r.Max
+}
+
+// GPULimits holds allowed ranges for GPU tuning commands
+type GPULimits struct {
+	// Intensity is allowed intensity range
+	Intensity Range
+
+	// EngineClock is allowed GPU engine clock range in MHz
+	EngineClock Range
+
+	// MemoryClock is allowed memory clock range in MHz
+	MemoryClock Range
+
+	// FanPercent is allowed fan speed range in percent
+	FanPercent Range
+
+	// Voltage is allowed GPU core voltage range in volts
+	Voltage Range
+
+	// Powertune is allowed powertune range in percent
+	Powertune Range
+}
+
+// DefaultGPULimits is used when CGMiner.GPULimits is nil
+var DefaultGPULimits = GPULimits{
+	Intensity:   Range{Min: -10, Max: 31},
+	EngineClock: Range{Min: 100, Max: 3000},
+	MemoryClock: Range{Min: 100, Max: 3000},
+	FanPercent:  Range{Min: 0, Max: 100},
+	Voltage:     Range{Min: 0.5, Max: 1.5},
+	Powertune:   Range{Min: -50, Max: 50},
+}
+
+// readbackTolerance is allowed difference between requested and reported value,
+// as voltage might be reported with lower precision
+const readbackTolerance = 0.005
+
+// GPUSettingError is returned when GPU reports different value
+// after setting was applied.
+type GPUSettingError struct {
+	// GPU is device index
+	GPU int64
+
+	// Setting is API command name, e.g. "gpuengine"
+	Setting string
+
+	// Want is requested value
+	Want float64
+
+	// Got is value reported by device
+	Got float64
+}
+
+// Error implements error
+func (err *GPUSettingError) Error() string {
+	return fmt.Sprintf("GPU %d: %s was not applied: requested %v, device reports %v",
+		err.GPU, err.Setting, err.Want, err.Got)
+}
+
+// GPU returns information about single GPU. See the Devs struct.
+func (c *CGMiner) GPU(gpu int64) (*Devs, error) {
+	return c.GPUContext(context.Background(), gpu)
+}
+
+// GPUContext returns information about single GPU. See the Devs struct.
+func (c *CGMiner) GPUContext(ctx context.Context, gpu int64) (*Devs, error) {
+	return c.gpu(ctx, gpu, c.CallContext)
+}
+
+// gpu queries GPU with passed call function
+func (c *CGMiner) gpu(ctx context.Context, gpu int64, call func(ctx context.Context, cmd Command, out AbstractResponse) error) (*Devs, error) {
+	resp := new(gpuResponse)
+	if err := call(ctx, NewCommand("gpu", strconv.FormatInt(gpu, 10)), resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.GPU) < 1 {
+		return nil, errors.New("no GPU in JSON response")
+	}
+	if len(resp.GPU) > 1 {
+		return nil, errors.New("too many GPUs in JSON response")
+	}
+	return &resp.GPU[0], nil
+}
+
+// SetGPUIntensity sets GPU intensity and verifies that it was applied
+func (c *CGMiner) SetGPUIntensity(gpu int64, intensity int) error {
+	return c.SetGPUIntensityContext(context.Background(), gpu, intensity)
+}
+
+// SetGPUIntensityContext sets GPU intensity and verifies that it was applied
+func (c *CGMiner) SetGPUIntensityContext(ctx context.Context, gpu int64, intensity int) error {
+	return c.setGPU(ctx, gpu, "gpuintensity", float64(intensity), c.gpuLimits().Intensity,
+		strconv.Itoa(intensity), func(d *Devs) float64 {
+			v, err := strconv.ParseFloat(d.Intensity, 64)
+			if err != nil {
+				// dynamic intensity ("D") or unknown value
+				return math.NaN()
+			}
+			return v
+		})
+}
+
+// SetGPUEngineClock sets GPU engine clock in MHz and verifies that it was applied
+func (c *CGMiner) SetGPUEngineClock(gpu, mhz int64) error {
+	return c.SetGPUEngineClockContext(context.Background(), gpu, mhz)
+}
+
+// SetGPUEngineClockContext sets GPU engine clock in MHz and verifies that it was applied
+func (c *CGMiner) SetGPUEngineClockContext(ctx context.Context, gpu, mhz int64) error {
+	return c.setGPU(ctx, gpu, "gpuengine", float64(mhz), c.gpuLimits().EngineClock,
+		strconv.FormatInt(mhz, 10), func(d *Devs) float64 { return float64(d.GPUClock) })
+}
+
+// SetGPUMemoryClock sets GPU memory clock in MHz and verifies that it was applied
+func (c *CGMiner) SetGPUMemoryClock(gpu, mhz int64) error {
+	return c.SetGPUMemoryClockContext(context.Background(), gpu, mhz)
+}
+
+// SetGPUMemoryClockContext sets GPU memory clock in MHz and verifies that it was applied
+func (c *CGMiner) SetGPUMemoryClockContext(ctx context.Context, gpu, mhz int64) error {
+	return c.setGPU(ctx, gpu, "gpumem", float64(mhz), c.gpuLimits().MemoryClock,
+		strconv.FormatInt(mhz, 10), func(d *Devs) float64 { return float64(d.MemoryClock) })
+}
+
+// SetGPUFan sets GPU fan speed in percent and verifies that it was applied
+func (c *CGMiner) SetGPUFan(gpu, percent int64) error {
+	return c.SetGPUFanContext(context.Background(), gpu, percent)
+}
+
+// SetGPUFanContext sets GPU fan speed in percent and verifies that it was applied
+func (c *CGMiner) SetGPUFanContext(ctx context.Context, gpu, percent int64) error {
+	return c.setGPU(ctx, gpu, "gpufan", float64(percent), c.gpuLimits().FanPercent,
+		strconv.FormatInt(percent, 10), func(d *Devs) float64 { return float64(d.FanPercent) })
+}
+
+// SetGPUVoltage sets GPU core voltage in volts and verifies that it was applied
+func (c *CGMiner) SetGPUVoltage(gpu int64, volts float64) error {
+	return c.SetGPUVoltageContext(context.Background(), gpu, volts)
+}
+
+// SetGPUVoltageContext sets GPU core voltage in volts and verifies that it was applied
+func (c *CGMiner) SetGPUVoltageContext(ctx context.Context, gpu int64, volts float64) error {
+	return c.setGPU(ctx, gpu, "gpuvddc", volts, c.gpuLimits().Voltage,
+		strconv.FormatFloat(volts, 'f', 3, 64), func(d *Devs) float64 { return d.GPUVoltage })
+}
+
+// SetGPUPowertune sets GPU powertune in percent and verifies that it was applied
+func (c *CGMiner) SetGPUPowertune(gpu, percent int64) error {
+	return c.SetGPUPowertuneContext(context.Background(), gpu, percent)
+}
+
+// SetGPUPowertuneContext sets GPU powertune in percent and verifies that it was applied
+func (c *CGMiner) SetGPUPowertuneContext(ctx context.Context, gpu, percent int64) error {
+	return c.setGPU(ctx, gpu, "gpupowertune", float64(percent), c.gpuLimits().Powertune,
+		strconv.FormatInt(percent, 10), func(d *Devs) float64 { return float64(d.Powertune) })
+}
+
+func (c *CGMiner) gpuLimits() GPULimits {
+	if c.GPULimits != nil {
+		return *c.GPULimits
+	}
+	return DefaultGPULimits
+}
+
+// setGPU validates value, sends setting command and re-queries GPU
+// to check that value was applied. Readback bypasses Cache, as cached
+// or in-flight reply might be sent before the setting.
+func (c *CGMiner) setGPU(ctx context.Context, gpu int64, cmd string, want float64, limits Range, param string, readback func(d *Devs) float64) error {
+	if !limits.Contains(want) {
+		return fmt.Errorf("%s: %w: %v not in [%v, %v]", cmd, ErrOutOfRange, want, limits.Min, limits.Max)
+	}
+
+	parameter := strconv.FormatInt(gpu, 10) + "," + param
+	if err := c.CallContext(ctx, NewCommand(cmd, parameter), nil); err != nil {
+		return err
+	}
+
+	dev, err := c.gpu(ctx, gpu, c.call)
+	if err != nil {
+		return fmt.Errorf("%s: failed to verify setting: %w", cmd, err)
+	}
+
+	got := readback(dev)
+	if math.IsNaN(got) || math.Abs(got-want) > readbackTolerance {
+		return &GPUSettingError{GPU: gpu, Setting: cmd, Want: want, Got: got}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package cgminer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeGPU is stateful fake sgminer-compatible API which handles
+// "gpu" and GPU tuning commands through in-memory connection.
+type fakeGPU struct {
+	dev      Devs
+	commands []string
+
+	// ignore makes fake device to ignore setting command
+	ignore string
+}
+
+func (f *fakeGPU) dialer() Dialer {
+	return DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go f.serve(server)
+		return client, nil
+	})
+}
+
+func (f *fakeGPU) serve(conn net.Conn) {
+	defer conn.Close()
+	var cmd Command
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		return
+	}
+
+	f.commands = append(f.commands, cmd.Command+"|"+cmd.Parameter)
+	status := `[{"STATUS":"S","Code":1,"Msg":"ok"}]`
+	params := strings.Split(cmd.Parameter, ",")
+	if cmd.Command != "gpu" && cmd.Command != f.ignore && len(params) == 2 {
+		v, _ := strconv.ParseFloat(params[1], 64)
+		switch cmd.Command {
+		case "gpuintensity":
+			f.dev.Intensity = params[1]
+		case "gpuengine":
+			f.dev.GPUClock = int64(v)
+		case "gpumem":
+			f.dev.MemoryClock = int64(v)
+		case "gpufan":
+			f.dev.FanPercent = int64(v)
+		case "gpuvddc":
+			f.dev.GPUVoltage = v
+		case "gpupowertune":
+			f.dev.Powertune = int64(v)
+		}
+	}
+
+	rsp := fmt.Sprintf(`{"STATUS":%s,"id":1}`, status)
+	if cmd.Command == "gpu" {
+		dev, _ := json.Marshal(f.dev)
+		rsp = fmt.Sprintf(`{"STATUS":%s,"GPU":[%s],"id":1}`, status, dev)
+	}
+	_, _ = conn.Write(append([]byte(rsp), 0x00))
+}
+
+func TestGPUSettings(t *testing.T) {
+	fake := &fakeGPU{dev: Devs{GPU: 1, Intensity: "20"}}
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Dialer = fake.dialer()
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"gpuintensity|1,18", func() error { return miner.SetGPUIntensity(1, 18) }},
+		{"gpuengine|1,1100", func() error { return miner.SetGPUEngineClock(1, 1100) }},
+		{"gpumem|1,2000", func() error { return miner.SetGPUMemoryClock(1, 2000) }},
+		{"gpufan|1,70", func() error { return miner.SetGPUFan(1, 70) }},
+		{"gpuvddc|1,0.850", func() error { return miner.SetGPUVoltage(1, 0.85) }},
+		{"gpupowertune|1,-10", func() error { return miner.SetGPUPowertune(1, -10) }},
+	}
+
+	for _, step := range steps {
+		fake.commands = nil
+		if err := step.fn(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		// setting command should be followed by readback
+		expected := []string{step.name, "gpu|1"}
+		if strings.Join(fake.commands, " ") != strings.Join(expected, " ") {
+			t.Fatalf("unexpected commands: %v", fake.commands)
+		}
+	}
+
+	expected := Devs{GPU: 1, Intensity: "18", GPUClock: 1100, MemoryClock: 2000, FanPercent: 70, GPUVoltage: 0.85, Powertune: -10}
+	if fake.dev != expected {
+		t.Fatalf("unexpected device state: %+v", fake.dev)
+	}
+}
+
+func TestGPUSettings_OutOfRange(t *testing.T) {
+	fake := &fakeGPU{}
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Dialer = fake.dialer()
+
+	errs := []error{
+		miner.SetGPUIntensity(0, 40),
+		miner.SetGPUEngineClock(0, 5000),
+		miner.SetGPUFan(0, 101),
+		miner.SetGPUVoltage(0, 2),
+	}
+	for i, err := range errs {
+		if !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("%d: expected ErrOutOfRange, got %v", i, err)
+		}
+	}
+	if len(fake.commands) > 0 {
+		t.Fatalf("commands should not be sent: %v", fake.commands)
+	}
+
+	miner.GPULimits = &GPULimits{EngineClock: Range{Min: 500, Max: 6000}}
+	if err := miner.SetGPUEngineClock(0, 5000); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGPUSettings_NotApplied(t *testing.T) {
+	fake := &fakeGPU{dev: Devs{GPU: 2, FanPercent: 40}, ignore: "gpufan"}
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Dialer = fake.dialer()
+
+	err := miner.SetGPUFan(2, 80)
+	var settingErr *GPUSettingError
+	if !errors.As(err, &settingErr) {
+		t.Fatalf("expected GPUSettingError, got %v", err)
+	}
+
+	expected := GPUSettingError{GPU: 2, Setting: "gpufan", Want: 80, Got: 40}
+	if *settingErr != expected {
+		t.Fatalf("unexpected error: %+v", settingErr)
+	}
+}
+
+func TestGPUSettings_ReadOnly(t *testing.T) {
+	fake := &fakeGPU{}
+	miner := NewCGMiner(ip, 4028, minerTimeout)
+	miner.Dialer = fake.dialer()
+	miner.ReadOnly = true
+
+	if err := miner.SetGPUFan(0, 50); err != ErrReadOnly {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/sokdak/go-teamredminer-api/grpcapi/minerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Scope is token permission
+type Scope string
+
+const (
+	// ReadScope allows methods which only read miner state
+	ReadScope Scope = "read"
+
+	// WriteScope allows methods which change pools or miner process state
+	WriteScope Scope = "write"
+)
+
+// writeMethods lists MinerService methods which require WriteScope
+var writeMethods = map[string]bool{
+	"AddPool":     true,
+	"EnablePool":  true,
+	"DisablePool": true,
+	"SwitchPool":  true,
+	"RemovePool":  true,
+	"Restart":     true,
+	"Quit":        true,
+}
+
+// methodScope returns scope required to call method by its full name
+func methodScope(fullMethod string) Scope {
+	prefix := "/" + minerpb.MinerService_ServiceDesc.ServiceName + "/"
+	if strings.HasPrefix(fullMethod, prefix) && writeMethods[fullMethod[len(prefix):]] {
+		return WriteScope
+	}
+	return ReadScope
+}
+
+// UnaryInterceptor is grpc.UnaryServerInterceptor which checks
+// bearer token of request against Server.Tokens.
+func (s *Server) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx, methodScope(info.FullMethod)); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor is grpc.StreamServerInterceptor which checks
+// bearer token of stream against Server.Tokens.
+func (s *Server) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(ss.Context(), methodScope(info.FullMethod)); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize checks that bearer token from "authorization" metadata
+// has passed scope
+func (s *Server) authorize(ctx context.Context, scope Scope) error {
+	if s.Tokens == nil {
+		return nil
+	}
+
+	token := bearerToken(ctx)
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	scopes, ok := s.lookupToken(token)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "invalid token")
+	}
+	for _, sc := range scopes {
+		if sc == scope {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "token has no %s scope", scope)
+}
+
+// lookupToken returns scopes of token. Token is compared with all
+// configured tokens in constant time, so response time doesn't tell
+// how much of token is guessed.
+func (s *Server) lookupToken(token string) ([]Scope, bool) {
+	var (
+		scopes []Scope
+		found  bool
+	)
+	for t, sc := range s.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			scopes, found = sc, true
+		}
+	}
+	return scopes, found
+}
+
+// bearerToken returns token from "authorization" metadata
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	const prefix = "Bearer "
+	for _, auth := range md.Get("authorization") {
+		if len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+			return strings.TrimSpace(auth[len(prefix):])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package grpcapi
+
+import (
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"github.com/sokdak/go-teamredminer-api/grpcapi/minerpb"
+)
+
+func versionProto(v *cgminer.Version) *minerpb.VersionResponse {
+	return &minerpb.VersionResponse{
+		Bmminer:     v.BMMiner,
+		Api:         v.API,
+		Miner:       v.Miner,
+		CompileTime: v.CompileTime,
+		Type:        v.Type,
+	}
+}
+
+func summaryProto(s *cgminer.Summary) *minerpb.Summary {
+	return &minerpb.Summary{
+		Accepted:              s.Accepted,
+		BestShare:             s.BestShare,
+		DeviceHardwarePercent: s.DeviceHardwarePercent,
+		DeviceRejectedPercent: s.DeviceRejectedPercent,
+		DifficultyAccepted:    s.DifficultyAccepted,
+		DifficultyRejected:    s.DifficultyRejected,
+		DifficultyStale:       s.DifficultyStale,
+		Discarded:             s.Discarded,
+		Elapsed:               s.Elapsed,
+		FoundBlocks:           s.FoundBlocks,
+		GetFailures:           s.GetFailures,
+		Getworks:              s.Getworks,
+		HardwareErrors:        s.HardwareErrors,
+		LocalWork:             s.LocalWork,
+		Mhs_5S:                s.MHS5s,
+		MhsAv:                 s.MHSav,
+		Ghs_5S:                s.GHS5s,
+		GhsAv:                 s.GHSav,
+		NetworkBlocks:         s.NetworkBlocks,
+		PoolRejectedPercent:   s.PoolRejectedPercent,
+		PoolStalePercent:      s.PoolStalePercent,
+		Rejected:              s.Rejected,
+		RemoteFailures:        s.RemoteFailures,
+		Stale:                 s.Stale,
+		TotalMh:               s.TotalMH,
+		Utility:               s.Utility,
+		WorkUtility:           s.WorkUtility,
+		LastGetwork:           int64(s.LastGetWork),
+	}
+}
+
+func deviceProto(d *cgminer.Devs) *minerpb.Device {
+	return &minerpb.Device{
+		Gpu:                   d.GPU,
+		Enabled:               d.Enabled,
+		Status:                d.Status,
+		Temperature:           d.Temperature,
+		TemperatureJunction:   d.TemperatureJunction,
+		TemperatureMemory:     d.TemperatureMemory,
+		FanSpeed:              int64(d.FanSpeed),
+		FanPercent:            d.FanPercent,
+		GpuClock:              d.GPUClock,
+		MemoryClock:           d.MemoryClock,
+		GpuVoltage:            d.GPUVoltage,
+		PowerConsumption:      d.PowerConsumption,
+		Powertune:             d.Powertune,
+		MhsAv:                 d.MHSav,
+		Mhs_5S:                d.MHS5s,
+		Mhs_30S:               d.MHS30s,
+		Accepted:              d.Accepted,
+		Rejected:              d.Rejected,
+		HardwareErrors:        d.HardwareErrors,
+		Utility:               d.Utility,
+		Intensity:             d.Intensity,
+		LastSharePool:         d.LastSharePool,
+		LastShareTime:         d.LashShareTime,
+		TotalMh:               d.TotalMH,
+		Diff1Work:             d.Diff1Work,
+		DifficultyAccepted:    d.DifficultyAccepted,
+		DifficultyRejected:    d.DifficultyRejected,
+		LastShareDifficulty:   d.LastShareDifficulty,
+		LastValidWork:         d.LastValidWork,
+		DeviceHardwarePercent: d.DeviceHardware,
+		DeviceRejectedPercent: d.DeviceRejected,
+		DeviceElapsed:         d.DeviceElapsed,
+	}
+}
+
+func poolProto(p *cgminer.Pool) *minerpb.Pool {
+	return &minerpb.Pool{
+		Pool:                p.Pool,
+		Url:                 p.URL,
+		User:                p.User,
+		Status:              p.Status,
+		Priority:            p.Priority,
+		Quota:               p.Quota,
+		LongPoll:            p.LongPoll,
+		Getworks:            p.Getworks,
+		Accepted:            p.Accepted,
+		Rejected:            p.Rejected,
+		Works:               p.Works,
+		Discarded:           p.Discarded,
+		Stale:               p.Stale,
+		GetFailures:         p.GetFailures,
+		RemoteFailures:      p.RemoteFailures,
+		LastShareTime:       p.LastShareTime,
+		Diff1Shares:         p.Diff1Shares,
+		ProxyType:           p.ProxyType,
+		Proxy:               p.Proxy,
+		DifficultyAccepted:  p.DifficultyAccepted,
+		DifficultyRejected:  p.DifficultyRejected,
+		DifficultyStale:     p.DifficultyStale,
+		LastShareDifficulty: p.LastShareDifficulty,
+		HasStratum:          p.HasStratum,
+		StratumActive:       p.StratumActive,
+		StratumUrl:          p.StratumURL,
+		HasGbt:              p.HasGBT,
+		BestShare:           p.BestShare.Float64(),
+		PoolRejectedPercent: p.PoolRejectedPercent,
+		PoolStalePercent:    p.PoolStalePercent,
+	}
+}
+
+func statsProto(records []map[string]cgminer.Value) *minerpb.StatsResponse {
+	resp := &minerpb.StatsResponse{Records: make([]*minerpb.StatsRecord, 0, len(records))}
+	for _, rec := range records {
+		fields := make(map[string]string, len(rec))
+		for k, v := range rec {
+			fields[k] = v.String()
+		}
+		resp.Records = append(resp.Records, &minerpb.StatsRecord{Fields: fields})
+	}
+	return resp
+}
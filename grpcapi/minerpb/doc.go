@@ -0,0 +1,13 @@
+// Package minerpb contains generated protobuf messages and gRPC stubs
+// of MinerService, see miner.proto.
+//
+// Files are generated with protoc 3.21.12, protoc-gen-go v1.27.1 and
+// protoc-gen-go-grpc v1.1.0. Generate directives install pinned plugins
+// and refuse to run with other protoc version, so regenerated code
+// differs only by miner.proto changes.
+package minerpb
+
+//go:generate go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.27.1
+//go:generate go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.1.0
+//go:generate sh -c "protoc --version | grep -qx 'libprotoc 3.21.12' || { echo 'protoc 3.21.12 is required' >&2; exit 1; }"
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative miner.proto
@@ -0,0 +1,2299 @@
+// MinerService exposes cgminer-compatible miner API over gRPC.
+//
+// Generated code is committed, run `go generate` in this directory
+// after changing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.21.12
+// source: miner.proto
+
+package minerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// MinerRequest selects miner. Miner may be empty if server has single miner.
+type MinerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Miner string `protobuf:"bytes,1,opt,name=miner,proto3" json:"miner,omitempty"`
+}
+
+func (x *MinerRequest) Reset() {
+	*x = MinerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MinerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MinerRequest) ProtoMessage() {}
+
+func (x *MinerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MinerRequest.ProtoReflect.Descriptor instead.
+func (*MinerRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MinerRequest) GetMiner() string {
+	if x != nil {
+		return x.Miner
+	}
+	return ""
+}
+
+type PoolRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Miner string `protobuf:"bytes,1,opt,name=miner,proto3" json:"miner,omitempty"`
+	Pool  int64  `protobuf:"varint,2,opt,name=pool,proto3" json:"pool,omitempty"`
+}
+
+func (x *PoolRequest) Reset() {
+	*x = PoolRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolRequest) ProtoMessage() {}
+
+func (x *PoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolRequest.ProtoReflect.Descriptor instead.
+func (*PoolRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PoolRequest) GetMiner() string {
+	if x != nil {
+		return x.Miner
+	}
+	return ""
+}
+
+func (x *PoolRequest) GetPool() int64 {
+	if x != nil {
+		return x.Pool
+	}
+	return 0
+}
+
+type AddPoolRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Miner    string `protobuf:"bytes,1,opt,name=miner,proto3" json:"miner,omitempty"`
+	Url      string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	User     string `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Password string `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *AddPoolRequest) Reset() {
+	*x = AddPoolRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddPoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPoolRequest) ProtoMessage() {}
+
+func (x *AddPoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPoolRequest.ProtoReflect.Descriptor instead.
+func (*AddPoolRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AddPoolRequest) GetMiner() string {
+	if x != nil {
+		return x.Miner
+	}
+	return ""
+}
+
+func (x *AddPoolRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AddPoolRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AddPoolRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type CommandResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *CommandResponse) Reset() {
+	*x = CommandResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResponse) ProtoMessage() {}
+
+func (x *CommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
+func (*CommandResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{3}
+}
+
+type WatchSummaryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Miner string `protobuf:"bytes,1,opt,name=miner,proto3" json:"miner,omitempty"`
+	// interval between polls, server default is used if unset
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+}
+
+func (x *WatchSummaryRequest) Reset() {
+	*x = WatchSummaryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSummaryRequest) ProtoMessage() {}
+
+func (x *WatchSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSummaryRequest.ProtoReflect.Descriptor instead.
+func (*WatchSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchSummaryRequest) GetMiner() string {
+	if x != nil {
+		return x.Miner
+	}
+	return ""
+}
+
+func (x *WatchSummaryRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type SummaryUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Miner   string                 `protobuf:"bytes,1,opt,name=miner,proto3" json:"miner,omitempty"`
+	Time    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Summary *Summary               `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
+	// error is set if poll failed
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SummaryUpdate) Reset() {
+	*x = SummaryUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SummaryUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummaryUpdate) ProtoMessage() {}
+
+func (x *SummaryUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummaryUpdate.ProtoReflect.Descriptor instead.
+func (*SummaryUpdate) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SummaryUpdate) GetMiner() string {
+	if x != nil {
+		return x.Miner
+	}
+	return ""
+}
+
+func (x *SummaryUpdate) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *SummaryUpdate) GetSummary() *Summary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *SummaryUpdate) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type VersionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bmminer     string `protobuf:"bytes,1,opt,name=bmminer,proto3" json:"bmminer,omitempty"`
+	Api         string `protobuf:"bytes,2,opt,name=api,proto3" json:"api,omitempty"`
+	Miner       string `protobuf:"bytes,3,opt,name=miner,proto3" json:"miner,omitempty"`
+	CompileTime string `protobuf:"bytes,4,opt,name=compile_time,json=compileTime,proto3" json:"compile_time,omitempty"`
+	Type        string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+}
+
+func (x *VersionResponse) Reset() {
+	*x = VersionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionResponse) ProtoMessage() {}
+
+func (x *VersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionResponse.ProtoReflect.Descriptor instead.
+func (*VersionResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VersionResponse) GetBmminer() string {
+	if x != nil {
+		return x.Bmminer
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetApi() string {
+	if x != nil {
+		return x.Api
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetMiner() string {
+	if x != nil {
+		return x.Miner
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetCompileTime() string {
+	if x != nil {
+		return x.CompileTime
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type SummaryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Summary *Summary `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+}
+
+func (x *SummaryResponse) Reset() {
+	*x = SummaryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SummaryResponse) ProtoMessage() {}
+
+func (x *SummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SummaryResponse.ProtoReflect.Descriptor instead.
+func (*SummaryResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SummaryResponse) GetSummary() *Summary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type Summary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Accepted              int64   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	BestShare             float64 `protobuf:"fixed64,2,opt,name=best_share,json=bestShare,proto3" json:"best_share,omitempty"`
+	DeviceHardwarePercent float64 `protobuf:"fixed64,3,opt,name=device_hardware_percent,json=deviceHardwarePercent,proto3" json:"device_hardware_percent,omitempty"`
+	DeviceRejectedPercent float64 `protobuf:"fixed64,4,opt,name=device_rejected_percent,json=deviceRejectedPercent,proto3" json:"device_rejected_percent,omitempty"`
+	DifficultyAccepted    float64 `protobuf:"fixed64,5,opt,name=difficulty_accepted,json=difficultyAccepted,proto3" json:"difficulty_accepted,omitempty"`
+	DifficultyRejected    float64 `protobuf:"fixed64,6,opt,name=difficulty_rejected,json=difficultyRejected,proto3" json:"difficulty_rejected,omitempty"`
+	DifficultyStale       float64 `protobuf:"fixed64,7,opt,name=difficulty_stale,json=difficultyStale,proto3" json:"difficulty_stale,omitempty"`
+	Discarded             int64   `protobuf:"varint,8,opt,name=discarded,proto3" json:"discarded,omitempty"`
+	Elapsed               int64   `protobuf:"varint,9,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	FoundBlocks           int64   `protobuf:"varint,10,opt,name=found_blocks,json=foundBlocks,proto3" json:"found_blocks,omitempty"`
+	GetFailures           int64   `protobuf:"varint,11,opt,name=get_failures,json=getFailures,proto3" json:"get_failures,omitempty"`
+	Getworks              int64   `protobuf:"varint,12,opt,name=getworks,proto3" json:"getworks,omitempty"`
+	HardwareErrors        int64   `protobuf:"varint,13,opt,name=hardware_errors,json=hardwareErrors,proto3" json:"hardware_errors,omitempty"`
+	LocalWork             int64   `protobuf:"varint,14,opt,name=local_work,json=localWork,proto3" json:"local_work,omitempty"`
+	Mhs_5S                float64 `protobuf:"fixed64,15,opt,name=mhs_5s,json=mhs5s,proto3" json:"mhs_5s,omitempty"`
+	MhsAv                 float64 `protobuf:"fixed64,16,opt,name=mhs_av,json=mhsAv,proto3" json:"mhs_av,omitempty"`
+	Ghs_5S                float64 `protobuf:"fixed64,17,opt,name=ghs_5s,json=ghs5s,proto3" json:"ghs_5s,omitempty"`
+	GhsAv                 float64 `protobuf:"fixed64,18,opt,name=ghs_av,json=ghsAv,proto3" json:"ghs_av,omitempty"`
+	NetworkBlocks         int64   `protobuf:"varint,19,opt,name=network_blocks,json=networkBlocks,proto3" json:"network_blocks,omitempty"`
+	PoolRejectedPercent   float64 `protobuf:"fixed64,20,opt,name=pool_rejected_percent,json=poolRejectedPercent,proto3" json:"pool_rejected_percent,omitempty"`
+	PoolStalePercent      float64 `protobuf:"fixed64,21,opt,name=pool_stale_percent,json=poolStalePercent,proto3" json:"pool_stale_percent,omitempty"`
+	Rejected              int64   `protobuf:"varint,22,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	RemoteFailures        int64   `protobuf:"varint,23,opt,name=remote_failures,json=remoteFailures,proto3" json:"remote_failures,omitempty"`
+	Stale                 int64   `protobuf:"varint,24,opt,name=stale,proto3" json:"stale,omitempty"`
+	TotalMh               float64 `protobuf:"fixed64,25,opt,name=total_mh,json=totalMh,proto3" json:"total_mh,omitempty"`
+	Utility               float64 `protobuf:"fixed64,26,opt,name=utility,proto3" json:"utility,omitempty"`
+	WorkUtility           float64 `protobuf:"fixed64,27,opt,name=work_utility,json=workUtility,proto3" json:"work_utility,omitempty"`
+	LastGetwork           int64   `protobuf:"varint,28,opt,name=last_getwork,json=lastGetwork,proto3" json:"last_getwork,omitempty"`
+}
+
+func (x *Summary) Reset() {
+	*x = Summary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Summary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Summary) ProtoMessage() {}
+
+func (x *Summary) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Summary.ProtoReflect.Descriptor instead.
+func (*Summary) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Summary) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *Summary) GetBestShare() float64 {
+	if x != nil {
+		return x.BestShare
+	}
+	return 0
+}
+
+func (x *Summary) GetDeviceHardwarePercent() float64 {
+	if x != nil {
+		return x.DeviceHardwarePercent
+	}
+	return 0
+}
+
+func (x *Summary) GetDeviceRejectedPercent() float64 {
+	if x != nil {
+		return x.DeviceRejectedPercent
+	}
+	return 0
+}
+
+func (x *Summary) GetDifficultyAccepted() float64 {
+	if x != nil {
+		return x.DifficultyAccepted
+	}
+	return 0
+}
+
+func (x *Summary) GetDifficultyRejected() float64 {
+	if x != nil {
+		return x.DifficultyRejected
+	}
+	return 0
+}
+
+func (x *Summary) GetDifficultyStale() float64 {
+	if x != nil {
+		return x.DifficultyStale
+	}
+	return 0
+}
+
+func (x *Summary) GetDiscarded() int64 {
+	if x != nil {
+		return x.Discarded
+	}
+	return 0
+}
+
+func (x *Summary) GetElapsed() int64 {
+	if x != nil {
+		return x.Elapsed
+	}
+	return 0
+}
+
+func (x *Summary) GetFoundBlocks() int64 {
+	if x != nil {
+		return x.FoundBlocks
+	}
+	return 0
+}
+
+func (x *Summary) GetGetFailures() int64 {
+	if x != nil {
+		return x.GetFailures
+	}
+	return 0
+}
+
+func (x *Summary) GetGetworks() int64 {
+	if x != nil {
+		return x.Getworks
+	}
+	return 0
+}
+
+func (x *Summary) GetHardwareErrors() int64 {
+	if x != nil {
+		return x.HardwareErrors
+	}
+	return 0
+}
+
+func (x *Summary) GetLocalWork() int64 {
+	if x != nil {
+		return x.LocalWork
+	}
+	return 0
+}
+
+func (x *Summary) GetMhs_5S() float64 {
+	if x != nil {
+		return x.Mhs_5S
+	}
+	return 0
+}
+
+func (x *Summary) GetMhsAv() float64 {
+	if x != nil {
+		return x.MhsAv
+	}
+	return 0
+}
+
+func (x *Summary) GetGhs_5S() float64 {
+	if x != nil {
+		return x.Ghs_5S
+	}
+	return 0
+}
+
+func (x *Summary) GetGhsAv() float64 {
+	if x != nil {
+		return x.GhsAv
+	}
+	return 0
+}
+
+func (x *Summary) GetNetworkBlocks() int64 {
+	if x != nil {
+		return x.NetworkBlocks
+	}
+	return 0
+}
+
+func (x *Summary) GetPoolRejectedPercent() float64 {
+	if x != nil {
+		return x.PoolRejectedPercent
+	}
+	return 0
+}
+
+func (x *Summary) GetPoolStalePercent() float64 {
+	if x != nil {
+		return x.PoolStalePercent
+	}
+	return 0
+}
+
+func (x *Summary) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *Summary) GetRemoteFailures() int64 {
+	if x != nil {
+		return x.RemoteFailures
+	}
+	return 0
+}
+
+func (x *Summary) GetStale() int64 {
+	if x != nil {
+		return x.Stale
+	}
+	return 0
+}
+
+func (x *Summary) GetTotalMh() float64 {
+	if x != nil {
+		return x.TotalMh
+	}
+	return 0
+}
+
+func (x *Summary) GetUtility() float64 {
+	if x != nil {
+		return x.Utility
+	}
+	return 0
+}
+
+func (x *Summary) GetWorkUtility() float64 {
+	if x != nil {
+		return x.WorkUtility
+	}
+	return 0
+}
+
+func (x *Summary) GetLastGetwork() int64 {
+	if x != nil {
+		return x.LastGetwork
+	}
+	return 0
+}
+
+type DevsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Devs []*Device `protobuf:"bytes,1,rep,name=devs,proto3" json:"devs,omitempty"`
+}
+
+func (x *DevsResponse) Reset() {
+	*x = DevsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DevsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevsResponse) ProtoMessage() {}
+
+func (x *DevsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevsResponse.ProtoReflect.Descriptor instead.
+func (*DevsResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DevsResponse) GetDevs() []*Device {
+	if x != nil {
+		return x.Devs
+	}
+	return nil
+}
+
+type Device struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Gpu                   int64   `protobuf:"varint,1,opt,name=gpu,proto3" json:"gpu,omitempty"`
+	Enabled               string  `protobuf:"bytes,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Status                string  `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Temperature           float64 `protobuf:"fixed64,4,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	TemperatureJunction   float64 `protobuf:"fixed64,5,opt,name=temperature_junction,json=temperatureJunction,proto3" json:"temperature_junction,omitempty"`
+	TemperatureMemory     float64 `protobuf:"fixed64,6,opt,name=temperature_memory,json=temperatureMemory,proto3" json:"temperature_memory,omitempty"`
+	FanSpeed              int64   `protobuf:"varint,7,opt,name=fan_speed,json=fanSpeed,proto3" json:"fan_speed,omitempty"`
+	FanPercent            int64   `protobuf:"varint,8,opt,name=fan_percent,json=fanPercent,proto3" json:"fan_percent,omitempty"`
+	GpuClock              int64   `protobuf:"varint,9,opt,name=gpu_clock,json=gpuClock,proto3" json:"gpu_clock,omitempty"`
+	MemoryClock           int64   `protobuf:"varint,10,opt,name=memory_clock,json=memoryClock,proto3" json:"memory_clock,omitempty"`
+	GpuVoltage            float64 `protobuf:"fixed64,11,opt,name=gpu_voltage,json=gpuVoltage,proto3" json:"gpu_voltage,omitempty"`
+	PowerConsumption      float64 `protobuf:"fixed64,12,opt,name=power_consumption,json=powerConsumption,proto3" json:"power_consumption,omitempty"`
+	Powertune             int64   `protobuf:"varint,13,opt,name=powertune,proto3" json:"powertune,omitempty"`
+	MhsAv                 float64 `protobuf:"fixed64,14,opt,name=mhs_av,json=mhsAv,proto3" json:"mhs_av,omitempty"`
+	Mhs_5S                float64 `protobuf:"fixed64,15,opt,name=mhs_5s,json=mhs5s,proto3" json:"mhs_5s,omitempty"`
+	Mhs_30S               float64 `protobuf:"fixed64,16,opt,name=mhs_30s,json=mhs30s,proto3" json:"mhs_30s,omitempty"`
+	Accepted              int64   `protobuf:"varint,17,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected              int64   `protobuf:"varint,18,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	HardwareErrors        int64   `protobuf:"varint,19,opt,name=hardware_errors,json=hardwareErrors,proto3" json:"hardware_errors,omitempty"`
+	Utility               float64 `protobuf:"fixed64,20,opt,name=utility,proto3" json:"utility,omitempty"`
+	Intensity             string  `protobuf:"bytes,21,opt,name=intensity,proto3" json:"intensity,omitempty"`
+	LastSharePool         int64   `protobuf:"varint,22,opt,name=last_share_pool,json=lastSharePool,proto3" json:"last_share_pool,omitempty"`
+	LastShareTime         int64   `protobuf:"varint,23,opt,name=last_share_time,json=lastShareTime,proto3" json:"last_share_time,omitempty"`
+	TotalMh               float64 `protobuf:"fixed64,24,opt,name=total_mh,json=totalMh,proto3" json:"total_mh,omitempty"`
+	Diff1Work             float64 `protobuf:"fixed64,25,opt,name=diff1_work,json=diff1Work,proto3" json:"diff1_work,omitempty"`
+	DifficultyAccepted    float64 `protobuf:"fixed64,26,opt,name=difficulty_accepted,json=difficultyAccepted,proto3" json:"difficulty_accepted,omitempty"`
+	DifficultyRejected    float64 `protobuf:"fixed64,27,opt,name=difficulty_rejected,json=difficultyRejected,proto3" json:"difficulty_rejected,omitempty"`
+	LastShareDifficulty   float64 `protobuf:"fixed64,28,opt,name=last_share_difficulty,json=lastShareDifficulty,proto3" json:"last_share_difficulty,omitempty"`
+	LastValidWork         int64   `protobuf:"varint,29,opt,name=last_valid_work,json=lastValidWork,proto3" json:"last_valid_work,omitempty"`
+	DeviceHardwarePercent float64 `protobuf:"fixed64,30,opt,name=device_hardware_percent,json=deviceHardwarePercent,proto3" json:"device_hardware_percent,omitempty"`
+	DeviceRejectedPercent float64 `protobuf:"fixed64,31,opt,name=device_rejected_percent,json=deviceRejectedPercent,proto3" json:"device_rejected_percent,omitempty"`
+	DeviceElapsed         int64   `protobuf:"varint,32,opt,name=device_elapsed,json=deviceElapsed,proto3" json:"device_elapsed,omitempty"`
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Device) GetGpu() int64 {
+	if x != nil {
+		return x.Gpu
+	}
+	return 0
+}
+
+func (x *Device) GetEnabled() string {
+	if x != nil {
+		return x.Enabled
+	}
+	return ""
+}
+
+func (x *Device) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Device) GetTemperature() float64 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *Device) GetTemperatureJunction() float64 {
+	if x != nil {
+		return x.TemperatureJunction
+	}
+	return 0
+}
+
+func (x *Device) GetTemperatureMemory() float64 {
+	if x != nil {
+		return x.TemperatureMemory
+	}
+	return 0
+}
+
+func (x *Device) GetFanSpeed() int64 {
+	if x != nil {
+		return x.FanSpeed
+	}
+	return 0
+}
+
+func (x *Device) GetFanPercent() int64 {
+	if x != nil {
+		return x.FanPercent
+	}
+	return 0
+}
+
+func (x *Device) GetGpuClock() int64 {
+	if x != nil {
+		return x.GpuClock
+	}
+	return 0
+}
+
+func (x *Device) GetMemoryClock() int64 {
+	if x != nil {
+		return x.MemoryClock
+	}
+	return 0
+}
+
+func (x *Device) GetGpuVoltage() float64 {
+	if x != nil {
+		return x.GpuVoltage
+	}
+	return 0
+}
+
+func (x *Device) GetPowerConsumption() float64 {
+	if x != nil {
+		return x.PowerConsumption
+	}
+	return 0
+}
+
+func (x *Device) GetPowertune() int64 {
+	if x != nil {
+		return x.Powertune
+	}
+	return 0
+}
+
+func (x *Device) GetMhsAv() float64 {
+	if x != nil {
+		return x.MhsAv
+	}
+	return 0
+}
+
+func (x *Device) GetMhs_5S() float64 {
+	if x != nil {
+		return x.Mhs_5S
+	}
+	return 0
+}
+
+func (x *Device) GetMhs_30S() float64 {
+	if x != nil {
+		return x.Mhs_30S
+	}
+	return 0
+}
+
+func (x *Device) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *Device) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *Device) GetHardwareErrors() int64 {
+	if x != nil {
+		return x.HardwareErrors
+	}
+	return 0
+}
+
+func (x *Device) GetUtility() float64 {
+	if x != nil {
+		return x.Utility
+	}
+	return 0
+}
+
+func (x *Device) GetIntensity() string {
+	if x != nil {
+		return x.Intensity
+	}
+	return ""
+}
+
+func (x *Device) GetLastSharePool() int64 {
+	if x != nil {
+		return x.LastSharePool
+	}
+	return 0
+}
+
+func (x *Device) GetLastShareTime() int64 {
+	if x != nil {
+		return x.LastShareTime
+	}
+	return 0
+}
+
+func (x *Device) GetTotalMh() float64 {
+	if x != nil {
+		return x.TotalMh
+	}
+	return 0
+}
+
+func (x *Device) GetDiff1Work() float64 {
+	if x != nil {
+		return x.Diff1Work
+	}
+	return 0
+}
+
+func (x *Device) GetDifficultyAccepted() float64 {
+	if x != nil {
+		return x.DifficultyAccepted
+	}
+	return 0
+}
+
+func (x *Device) GetDifficultyRejected() float64 {
+	if x != nil {
+		return x.DifficultyRejected
+	}
+	return 0
+}
+
+func (x *Device) GetLastShareDifficulty() float64 {
+	if x != nil {
+		return x.LastShareDifficulty
+	}
+	return 0
+}
+
+func (x *Device) GetLastValidWork() int64 {
+	if x != nil {
+		return x.LastValidWork
+	}
+	return 0
+}
+
+func (x *Device) GetDeviceHardwarePercent() float64 {
+	if x != nil {
+		return x.DeviceHardwarePercent
+	}
+	return 0
+}
+
+func (x *Device) GetDeviceRejectedPercent() float64 {
+	if x != nil {
+		return x.DeviceRejectedPercent
+	}
+	return 0
+}
+
+func (x *Device) GetDeviceElapsed() int64 {
+	if x != nil {
+		return x.DeviceElapsed
+	}
+	return 0
+}
+
+type PoolsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pools []*Pool `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty"`
+}
+
+func (x *PoolsResponse) Reset() {
+	*x = PoolsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PoolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolsResponse) ProtoMessage() {}
+
+func (x *PoolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolsResponse.ProtoReflect.Descriptor instead.
+func (*PoolsResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PoolsResponse) GetPools() []*Pool {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+type Pool struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pool                int64   `protobuf:"varint,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Url                 string  `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	User                string  `protobuf:"bytes,3,opt,name=user,proto3" json:"user,omitempty"`
+	Status              string  `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Priority            int64   `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	Quota               int64   `protobuf:"varint,6,opt,name=quota,proto3" json:"quota,omitempty"`
+	LongPoll            string  `protobuf:"bytes,7,opt,name=long_poll,json=longPoll,proto3" json:"long_poll,omitempty"`
+	Getworks            int64   `protobuf:"varint,8,opt,name=getworks,proto3" json:"getworks,omitempty"`
+	Accepted            int64   `protobuf:"varint,9,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected            int64   `protobuf:"varint,10,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Works               int64   `protobuf:"varint,11,opt,name=works,proto3" json:"works,omitempty"`
+	Discarded           int64   `protobuf:"varint,12,opt,name=discarded,proto3" json:"discarded,omitempty"`
+	Stale               int64   `protobuf:"varint,13,opt,name=stale,proto3" json:"stale,omitempty"`
+	GetFailures         int64   `protobuf:"varint,14,opt,name=get_failures,json=getFailures,proto3" json:"get_failures,omitempty"`
+	RemoteFailures      int64   `protobuf:"varint,15,opt,name=remote_failures,json=remoteFailures,proto3" json:"remote_failures,omitempty"`
+	LastShareTime       float64 `protobuf:"fixed64,16,opt,name=last_share_time,json=lastShareTime,proto3" json:"last_share_time,omitempty"`
+	Diff1Shares         float64 `protobuf:"fixed64,17,opt,name=diff1_shares,json=diff1Shares,proto3" json:"diff1_shares,omitempty"`
+	ProxyType           string  `protobuf:"bytes,18,opt,name=proxy_type,json=proxyType,proto3" json:"proxy_type,omitempty"`
+	Proxy               string  `protobuf:"bytes,19,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	DifficultyAccepted  float64 `protobuf:"fixed64,20,opt,name=difficulty_accepted,json=difficultyAccepted,proto3" json:"difficulty_accepted,omitempty"`
+	DifficultyRejected  float64 `protobuf:"fixed64,21,opt,name=difficulty_rejected,json=difficultyRejected,proto3" json:"difficulty_rejected,omitempty"`
+	DifficultyStale     float64 `protobuf:"fixed64,22,opt,name=difficulty_stale,json=difficultyStale,proto3" json:"difficulty_stale,omitempty"`
+	LastShareDifficulty float64 `protobuf:"fixed64,23,opt,name=last_share_difficulty,json=lastShareDifficulty,proto3" json:"last_share_difficulty,omitempty"`
+	HasStratum          bool    `protobuf:"varint,24,opt,name=has_stratum,json=hasStratum,proto3" json:"has_stratum,omitempty"`
+	StratumActive       bool    `protobuf:"varint,25,opt,name=stratum_active,json=stratumActive,proto3" json:"stratum_active,omitempty"`
+	StratumUrl          string  `protobuf:"bytes,26,opt,name=stratum_url,json=stratumUrl,proto3" json:"stratum_url,omitempty"`
+	HasGbt              bool    `protobuf:"varint,27,opt,name=has_gbt,json=hasGbt,proto3" json:"has_gbt,omitempty"`
+	BestShare           float64 `protobuf:"fixed64,28,opt,name=best_share,json=bestShare,proto3" json:"best_share,omitempty"`
+	PoolRejectedPercent float64 `protobuf:"fixed64,29,opt,name=pool_rejected_percent,json=poolRejectedPercent,proto3" json:"pool_rejected_percent,omitempty"`
+	PoolStalePercent    float64 `protobuf:"fixed64,30,opt,name=pool_stale_percent,json=poolStalePercent,proto3" json:"pool_stale_percent,omitempty"`
+}
+
+func (x *Pool) Reset() {
+	*x = Pool{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pool) ProtoMessage() {}
+
+func (x *Pool) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pool.ProtoReflect.Descriptor instead.
+func (*Pool) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Pool) GetPool() int64 {
+	if x != nil {
+		return x.Pool
+	}
+	return 0
+}
+
+func (x *Pool) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Pool) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Pool) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Pool) GetPriority() int64 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Pool) GetQuota() int64 {
+	if x != nil {
+		return x.Quota
+	}
+	return 0
+}
+
+func (x *Pool) GetLongPoll() string {
+	if x != nil {
+		return x.LongPoll
+	}
+	return ""
+}
+
+func (x *Pool) GetGetworks() int64 {
+	if x != nil {
+		return x.Getworks
+	}
+	return 0
+}
+
+func (x *Pool) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *Pool) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *Pool) GetWorks() int64 {
+	if x != nil {
+		return x.Works
+	}
+	return 0
+}
+
+func (x *Pool) GetDiscarded() int64 {
+	if x != nil {
+		return x.Discarded
+	}
+	return 0
+}
+
+func (x *Pool) GetStale() int64 {
+	if x != nil {
+		return x.Stale
+	}
+	return 0
+}
+
+func (x *Pool) GetGetFailures() int64 {
+	if x != nil {
+		return x.GetFailures
+	}
+	return 0
+}
+
+func (x *Pool) GetRemoteFailures() int64 {
+	if x != nil {
+		return x.RemoteFailures
+	}
+	return 0
+}
+
+func (x *Pool) GetLastShareTime() float64 {
+	if x != nil {
+		return x.LastShareTime
+	}
+	return 0
+}
+
+func (x *Pool) GetDiff1Shares() float64 {
+	if x != nil {
+		return x.Diff1Shares
+	}
+	return 0
+}
+
+func (x *Pool) GetProxyType() string {
+	if x != nil {
+		return x.ProxyType
+	}
+	return ""
+}
+
+func (x *Pool) GetProxy() string {
+	if x != nil {
+		return x.Proxy
+	}
+	return ""
+}
+
+func (x *Pool) GetDifficultyAccepted() float64 {
+	if x != nil {
+		return x.DifficultyAccepted
+	}
+	return 0
+}
+
+func (x *Pool) GetDifficultyRejected() float64 {
+	if x != nil {
+		return x.DifficultyRejected
+	}
+	return 0
+}
+
+func (x *Pool) GetDifficultyStale() float64 {
+	if x != nil {
+		return x.DifficultyStale
+	}
+	return 0
+}
+
+func (x *Pool) GetLastShareDifficulty() float64 {
+	if x != nil {
+		return x.LastShareDifficulty
+	}
+	return 0
+}
+
+func (x *Pool) GetHasStratum() bool {
+	if x != nil {
+		return x.HasStratum
+	}
+	return false
+}
+
+func (x *Pool) GetStratumActive() bool {
+	if x != nil {
+		return x.StratumActive
+	}
+	return false
+}
+
+func (x *Pool) GetStratumUrl() string {
+	if x != nil {
+		return x.StratumUrl
+	}
+	return ""
+}
+
+func (x *Pool) GetHasGbt() bool {
+	if x != nil {
+		return x.HasGbt
+	}
+	return false
+}
+
+func (x *Pool) GetBestShare() float64 {
+	if x != nil {
+		return x.BestShare
+	}
+	return 0
+}
+
+func (x *Pool) GetPoolRejectedPercent() float64 {
+	if x != nil {
+		return x.PoolRejectedPercent
+	}
+	return 0
+}
+
+func (x *Pool) GetPoolStalePercent() float64 {
+	if x != nil {
+		return x.PoolStalePercent
+	}
+	return 0
+}
+
+// StatsResponse holds raw STATS records, values are formatted as strings
+// since their set depends on miner vendor.
+type StatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Records []*StatsRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+}
+
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsResponse) ProtoMessage() {}
+
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StatsResponse) GetRecords() []*StatsRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type StatsRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Fields map[string]string `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *StatsRecord) Reset() {
+	*x = StatsRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatsRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRecord) ProtoMessage() {}
+
+func (x *StatsRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsRecord.ProtoReflect.Descriptor instead.
+func (*StatsRecord) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StatsRecord) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type DevDetailsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Details []*DeviceDetail `protobuf:"bytes,1,rep,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *DevDetailsResponse) Reset() {
+	*x = DevDetailsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DevDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevDetailsResponse) ProtoMessage() {}
+
+func (x *DevDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevDetailsResponse.ProtoReflect.Descriptor instead.
+func (*DevDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DevDetailsResponse) GetDetails() []*DeviceDetail {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type DeviceDetail struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Model      string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Kernel     string `protobuf:"bytes,3,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	DevicePath string `protobuf:"bytes,4,opt,name=device_path,json=devicePath,proto3" json:"device_path,omitempty"`
+}
+
+func (x *DeviceDetail) Reset() {
+	*x = DeviceDetail{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_miner_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeviceDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceDetail) ProtoMessage() {}
+
+func (x *DeviceDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_miner_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceDetail.ProtoReflect.Descriptor instead.
+func (*DeviceDetail) Descriptor() ([]byte, []int) {
+	return file_miner_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeviceDetail) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeviceDetail) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *DeviceDetail) GetKernel() string {
+	if x != nil {
+		return x.Kernel
+	}
+	return ""
+}
+
+func (x *DeviceDetail) GetDevicePath() string {
+	if x != nil {
+		return x.DevicePath
+	}
+	return ""
+}
+
+var File_miner_proto protoreflect.FileDescriptor
+
+var file_miner_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x74,
+	0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x1a, 0x1e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x24, 0x0a, 0x0c,
+	0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x6d, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x69, 0x6e,
+	0x65, 0x72, 0x22, 0x37, 0x0a, 0x0b, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x22, 0x68, 0x0a, 0x0e, 0x41,
+	0x64, 0x64, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x69,
+	0x6e, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x11, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x62, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6d, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x9c, 0x01, 0x0a,
+	0x0d, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d,
+	0x69, 0x6e, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x07, 0x73, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x8a, 0x01, 0x0a, 0x0f,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x62, 0x6d, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x62, 0x6d, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x70, 0x69,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x70, 0x69, 0x12, 0x14, 0x0a, 0x05, 0x6d,
+	0x69, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x69, 0x6e, 0x65,
+	0x72, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x69, 0x6c, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x42, 0x0a, 0x0f, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x73,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74,
+	0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0xde, 0x07, 0x0a,
+	0x07, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x65, 0x73, 0x74, 0x5f, 0x73, 0x68, 0x61,
+	0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x62, 0x65, 0x73, 0x74, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x12, 0x36, 0x0a, 0x17, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x68, 0x61,
+	0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x15, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x48, 0x61, 0x72, 0x64,
+	0x77, 0x61, 0x72, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x15, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x50, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74,
+	0x79, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x12, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x41, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x65, 0x64, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c,
+	0x74, 0x79, 0x5f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x12, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75,
+	0x6c, 0x74, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0f, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x53, 0x74, 0x61, 0x6c, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x65, 0x64, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x65, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x66, 0x6f, 0x75, 0x6e,
+	0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x66, 0x6f, 0x75, 0x6e, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x67,
+	0x65, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0b, 0x67, 0x65, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x67, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x67, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x68, 0x61,
+	0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0e, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x77, 0x6f, 0x72,
+	0x6b, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x57, 0x6f,
+	0x72, 0x6b, 0x12, 0x15, 0x0a, 0x06, 0x6d, 0x68, 0x73, 0x5f, 0x35, 0x73, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x6d, 0x68, 0x73, 0x35, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x6d, 0x68, 0x73,
+	0x5f, 0x61, 0x76, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x6d, 0x68, 0x73, 0x41, 0x76,
+	0x12, 0x15, 0x0a, 0x06, 0x67, 0x68, 0x73, 0x5f, 0x35, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x67, 0x68, 0x73, 0x35, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x67, 0x68, 0x73, 0x5f, 0x61,
+	0x76, 0x18, 0x12, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x67, 0x68, 0x73, 0x41, 0x76, 0x12, 0x25,
+	0x0a, 0x0e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x18, 0x13, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x72, 0x65,
+	0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x14,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x13, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x6f, 0x6f,
+	0x6c, 0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18,
+	0x15, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x70, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x6c, 0x65,
+	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x6c, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6d, 0x68, 0x18, 0x19,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x68, 0x12, 0x18, 0x0a,
+	0x07, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07,
+	0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x5f,
+	0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x77,
+	0x6f, 0x72, 0x6b, 0x55, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x67, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x47, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x22, 0x38, 0x0a,
+	0x0c, 0x44, 0x65, 0x76, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x04, 0x64, 0x65, 0x76, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x72,
+	0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x52, 0x04, 0x64, 0x65, 0x76, 0x73, 0x22, 0xf9, 0x08, 0x0a, 0x06, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x67, 0x70, 0x75, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x03, 0x67, 0x70, 0x75, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x74, 0x65, 0x6d,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x31, 0x0a, 0x14, 0x74, 0x65, 0x6d, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6a, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x13, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x4a, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x12, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x61,
+	0x6e, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66,
+	0x61, 0x6e, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x61, 0x6e, 0x5f, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x61,
+	0x6e, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x70, 0x75, 0x5f,
+	0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x67, 0x70, 0x75,
+	0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f,
+	0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x1f, 0x0a, 0x0b, 0x67, 0x70, 0x75, 0x5f,
+	0x76, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x67,
+	0x70, 0x75, 0x56, 0x6f, 0x6c, 0x74, 0x61, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x70, 0x6f, 0x77,
+	0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x73, 0x75,
+	0x6d, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x74,
+	0x75, 0x6e, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x70, 0x6f, 0x77, 0x65, 0x72,
+	0x74, 0x75, 0x6e, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6d, 0x68, 0x73, 0x5f, 0x61, 0x76, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x6d, 0x68, 0x73, 0x41, 0x76, 0x12, 0x15, 0x0a, 0x06, 0x6d,
+	0x68, 0x73, 0x5f, 0x35, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x6d, 0x68, 0x73,
+	0x35, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x68, 0x73, 0x5f, 0x33, 0x30, 0x73, 0x18, 0x10, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x06, 0x6d, 0x68, 0x73, 0x33, 0x30, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x61,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x61,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x68, 0x61,
+	0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x75, 0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x14, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x75,
+	0x74, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x73,
+	0x69, 0x74, 0x79, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x68, 0x61,
+	0x72, 0x65, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c,
+	0x61, 0x73, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x26, 0x0a, 0x0f,
+	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x17, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6d, 0x68,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x68, 0x12,
+	0x1d, 0x0a, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x31, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x19, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x64, 0x69, 0x66, 0x66, 0x31, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x2f,
+	0x0a, 0x13, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x5f, 0x61, 0x63, 0x63,
+	0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x64, 0x69, 0x66,
+	0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12,
+	0x2f, 0x0a, 0x13, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x5f, 0x72, 0x65,
+	0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x64, 0x69,
+	0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x12, 0x32, 0x0a, 0x15, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x64,
+	0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x13, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63,
+	0x75, 0x6c, 0x74, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6c,
+	0x61, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x57, 0x6f, 0x72, 0x6b, 0x12, 0x36, 0x0a, 0x17,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f,
+	0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x01, 0x52, 0x15, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x48, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x50, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x72,
+	0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18,
+	0x1f, 0x20, 0x01, 0x28, 0x01, 0x52, 0x15, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x18, 0x20,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x45, 0x6c, 0x61, 0x70,
+	0x73, 0x65, 0x64, 0x22, 0x39, 0x0a, 0x0d, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x05, 0x70, 0x6f, 0x6f, 0x6c, 0x73, 0x22, 0xd5,
+	0x07, 0x0a, 0x04, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a,
+	0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x72, 0x69,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x6c,
+	0x6f, 0x6e, 0x67, 0x5f, 0x70, 0x6f, 0x6c, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x6c, 0x6f, 0x6e, 0x67, 0x50, 0x6f, 0x6c, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x67, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x67, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x77, 0x6f, 0x72,
+	0x6b, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x65, 0x64, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x65, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x67, 0x65, 0x74, 0x5f, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x67, 0x65,
+	0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x6c, 0x61, 0x73,
+	0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69,
+	0x66, 0x66, 0x31, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0b, 0x64, 0x69, 0x66, 0x66, 0x31, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79,
+	0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x12, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x41, 0x63, 0x63, 0x65, 0x70,
+	0x74, 0x65, 0x64, 0x12, 0x2f, 0x0a, 0x13, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74,
+	0x79, 0x5f, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x15, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x12, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x52, 0x65, 0x6a, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c,
+	0x74, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f,
+	0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x53, 0x74, 0x61, 0x6c, 0x65, 0x12,
+	0x32, 0x0a, 0x15, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x64, 0x69,
+	0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x13,
+	0x6c, 0x61, 0x73, 0x74, 0x53, 0x68, 0x61, 0x72, 0x65, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75,
+	0x6c, 0x74, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x61, 0x73, 0x5f, 0x73, 0x74, 0x72, 0x61, 0x74,
+	0x75, 0x6d, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x68, 0x61, 0x73, 0x53, 0x74, 0x72,
+	0x61, 0x74, 0x75, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x74, 0x72, 0x61, 0x74, 0x75, 0x6d, 0x5f,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x74,
+	0x72, 0x61, 0x74, 0x75, 0x6d, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x74, 0x72, 0x61, 0x74, 0x75, 0x6d, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x73, 0x74, 0x72, 0x61, 0x74, 0x75, 0x6d, 0x55, 0x72, 0x6c, 0x12, 0x17, 0x0a, 0x07,
+	0x68, 0x61, 0x73, 0x5f, 0x67, 0x62, 0x74, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x68,
+	0x61, 0x73, 0x47, 0x62, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x65, 0x73, 0x74, 0x5f, 0x73, 0x68,
+	0x61, 0x72, 0x65, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x62, 0x65, 0x73, 0x74, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x72, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x1d, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x13, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x6f, 0x6f, 0x6c,
+	0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x1e,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x70, 0x6f, 0x6f, 0x6c, 0x53, 0x74, 0x61, 0x6c, 0x65, 0x50,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0x44, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d,
+	0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x22, 0x87, 0x01, 0x0a,
+	0x0b, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x3d, 0x0a, 0x06,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x74,
+	0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x4a, 0x0a, 0x12, 0x44, 0x65, 0x76, 0x44, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x07,
+	0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x22, 0x6d, 0x0a, 0x0c, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x44, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x6b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c,
+	0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x32, 0xee, 0x07, 0x0a, 0x0c, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x44, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x2e,
+	0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6e,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e,
+	0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x07, 0x53, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x12, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e,
+	0x0a, 0x04, 0x44, 0x65, 0x76, 0x73, 0x12, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x76, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40,
+	0x0a, 0x05, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x12, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69,
+	0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x40, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e,
+	0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4a, 0x0a, 0x0a, 0x44, 0x65, 0x76, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x12, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x74,
+	0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x76, 0x44,
+	0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46,
+	0x0a, 0x07, 0x41, 0x64, 0x64, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x1c, 0x2e, 0x74, 0x72, 0x6d, 0x2e,
+	0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x6f, 0x6f, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69,
+	0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0a, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x19, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47,
+	0x0a, 0x0b, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x19, 0x2e,
+	0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d,
+	0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0a, 0x53, 0x77, 0x69, 0x74, 0x63,
+	0x68, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x19, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x46, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x19, 0x2e,
+	0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6f,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d,
+	0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x07, 0x52, 0x65, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x12, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a,
+	0x04, 0x51, 0x75, 0x69, 0x74, 0x12, 0x1a, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x69, 0x6e, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x50, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x21, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x72, 0x6d, 0x2e, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x30, 0x01, 0x42, 0x3f, 0x5a, 0x3d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x73, 0x6f, 0x6b, 0x64, 0x61, 0x6b, 0x2f, 0x67, 0x6f, 0x2d, 0x74, 0x65, 0x61, 0x6d, 0x72,
+	0x65, 0x64, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x72, 0x70, 0x63,
+	0x61, 0x70, 0x69, 0x2f, 0x6d, 0x69, 0x6e, 0x65, 0x72, 0x70, 0x62, 0x3b, 0x6d, 0x69, 0x6e, 0x65,
+	0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_miner_proto_rawDescOnce sync.Once
+	file_miner_proto_rawDescData = file_miner_proto_rawDesc
+)
+
+func file_miner_proto_rawDescGZIP() []byte {
+	file_miner_proto_rawDescOnce.Do(func() {
+		file_miner_proto_rawDescData = protoimpl.X.CompressGZIP(file_miner_proto_rawDescData)
+	})
+	return file_miner_proto_rawDescData
+}
+
+var file_miner_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_miner_proto_goTypes = []interface{}{
+	(*MinerRequest)(nil),          // 0: trm.miner.v1.MinerRequest
+	(*PoolRequest)(nil),           // 1: trm.miner.v1.PoolRequest
+	(*AddPoolRequest)(nil),        // 2: trm.miner.v1.AddPoolRequest
+	(*CommandResponse)(nil),       // 3: trm.miner.v1.CommandResponse
+	(*WatchSummaryRequest)(nil),   // 4: trm.miner.v1.WatchSummaryRequest
+	(*SummaryUpdate)(nil),         // 5: trm.miner.v1.SummaryUpdate
+	(*VersionResponse)(nil),       // 6: trm.miner.v1.VersionResponse
+	(*SummaryResponse)(nil),       // 7: trm.miner.v1.SummaryResponse
+	(*Summary)(nil),               // 8: trm.miner.v1.Summary
+	(*DevsResponse)(nil),          // 9: trm.miner.v1.DevsResponse
+	(*Device)(nil),                // 10: trm.miner.v1.Device
+	(*PoolsResponse)(nil),         // 11: trm.miner.v1.PoolsResponse
+	(*Pool)(nil),                  // 12: trm.miner.v1.Pool
+	(*StatsResponse)(nil),         // 13: trm.miner.v1.StatsResponse
+	(*StatsRecord)(nil),           // 14: trm.miner.v1.StatsRecord
+	(*DevDetailsResponse)(nil),    // 15: trm.miner.v1.DevDetailsResponse
+	(*DeviceDetail)(nil),          // 16: trm.miner.v1.DeviceDetail
+	nil,                           // 17: trm.miner.v1.StatsRecord.FieldsEntry
+	(*durationpb.Duration)(nil),   // 18: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
+}
+var file_miner_proto_depIdxs = []int32{
+	18, // 0: trm.miner.v1.WatchSummaryRequest.interval:type_name -> google.protobuf.Duration
+	19, // 1: trm.miner.v1.SummaryUpdate.time:type_name -> google.protobuf.Timestamp
+	8,  // 2: trm.miner.v1.SummaryUpdate.summary:type_name -> trm.miner.v1.Summary
+	8,  // 3: trm.miner.v1.SummaryResponse.summary:type_name -> trm.miner.v1.Summary
+	10, // 4: trm.miner.v1.DevsResponse.devs:type_name -> trm.miner.v1.Device
+	12, // 5: trm.miner.v1.PoolsResponse.pools:type_name -> trm.miner.v1.Pool
+	14, // 6: trm.miner.v1.StatsResponse.records:type_name -> trm.miner.v1.StatsRecord
+	17, // 7: trm.miner.v1.StatsRecord.fields:type_name -> trm.miner.v1.StatsRecord.FieldsEntry
+	16, // 8: trm.miner.v1.DevDetailsResponse.details:type_name -> trm.miner.v1.DeviceDetail
+	0,  // 9: trm.miner.v1.MinerService.Version:input_type -> trm.miner.v1.MinerRequest
+	0,  // 10: trm.miner.v1.MinerService.Summary:input_type -> trm.miner.v1.MinerRequest
+	0,  // 11: trm.miner.v1.MinerService.Devs:input_type -> trm.miner.v1.MinerRequest
+	0,  // 12: trm.miner.v1.MinerService.Pools:input_type -> trm.miner.v1.MinerRequest
+	0,  // 13: trm.miner.v1.MinerService.Stats:input_type -> trm.miner.v1.MinerRequest
+	0,  // 14: trm.miner.v1.MinerService.DevDetails:input_type -> trm.miner.v1.MinerRequest
+	2,  // 15: trm.miner.v1.MinerService.AddPool:input_type -> trm.miner.v1.AddPoolRequest
+	1,  // 16: trm.miner.v1.MinerService.EnablePool:input_type -> trm.miner.v1.PoolRequest
+	1,  // 17: trm.miner.v1.MinerService.DisablePool:input_type -> trm.miner.v1.PoolRequest
+	1,  // 18: trm.miner.v1.MinerService.SwitchPool:input_type -> trm.miner.v1.PoolRequest
+	1,  // 19: trm.miner.v1.MinerService.RemovePool:input_type -> trm.miner.v1.PoolRequest
+	0,  // 20: trm.miner.v1.MinerService.Restart:input_type -> trm.miner.v1.MinerRequest
+	0,  // 21: trm.miner.v1.MinerService.Quit:input_type -> trm.miner.v1.MinerRequest
+	4,  // 22: trm.miner.v1.MinerService.WatchSummary:input_type -> trm.miner.v1.WatchSummaryRequest
+	6,  // 23: trm.miner.v1.MinerService.Version:output_type -> trm.miner.v1.VersionResponse
+	7,  // 24: trm.miner.v1.MinerService.Summary:output_type -> trm.miner.v1.SummaryResponse
+	9,  // 25: trm.miner.v1.MinerService.Devs:output_type -> trm.miner.v1.DevsResponse
+	11, // 26: trm.miner.v1.MinerService.Pools:output_type -> trm.miner.v1.PoolsResponse
+	13, // 27: trm.miner.v1.MinerService.Stats:output_type -> trm.miner.v1.StatsResponse
+	15, // 28: trm.miner.v1.MinerService.DevDetails:output_type -> trm.miner.v1.DevDetailsResponse
+	3,  // 29: trm.miner.v1.MinerService.AddPool:output_type -> trm.miner.v1.CommandResponse
+	3,  // 30: trm.miner.v1.MinerService.EnablePool:output_type -> trm.miner.v1.CommandResponse
+	3,  // 31: trm.miner.v1.MinerService.DisablePool:output_type -> trm.miner.v1.CommandResponse
+	3,  // 32: trm.miner.v1.MinerService.SwitchPool:output_type -> trm.miner.v1.CommandResponse
+	3,  // 33: trm.miner.v1.MinerService.RemovePool:output_type -> trm.miner.v1.CommandResponse
+	3,  // 34: trm.miner.v1.MinerService.Restart:output_type -> trm.miner.v1.CommandResponse
+	3,  // 35: trm.miner.v1.MinerService.Quit:output_type -> trm.miner.v1.CommandResponse
+	5,  // 36: trm.miner.v1.MinerService.WatchSummary:output_type -> trm.miner.v1.SummaryUpdate
+	23, // [23:37] is the sub-list for method output_type
+	9,  // [9:23] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_miner_proto_init() }
+func file_miner_proto_init() {
+	if File_miner_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_miner_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MinerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PoolRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddPoolRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchSummaryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SummaryUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SummaryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Summary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DevsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Device); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PoolsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pool); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatsRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DevDetailsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_miner_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeviceDetail); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_miner_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_miner_proto_goTypes,
+		DependencyIndexes: file_miner_proto_depIdxs,
+		MessageInfos:      file_miner_proto_msgTypes,
+	}.Build()
+	File_miner_proto = out.File
+	file_miner_proto_rawDesc = nil
+	file_miner_proto_goTypes = nil
+	file_miner_proto_depIdxs = nil
+}
@@ -0,0 +1,601 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package minerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MinerServiceClient is the client API for MinerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MinerServiceClient interface {
+	Version(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+	Summary(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*SummaryResponse, error)
+	Devs(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*DevsResponse, error)
+	Pools(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*PoolsResponse, error)
+	Stats(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	DevDetails(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*DevDetailsResponse, error)
+	AddPool(ctx context.Context, in *AddPoolRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	EnablePool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	DisablePool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	SwitchPool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	RemovePool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	Restart(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	Quit(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	// WatchSummary polls miner summary and streams it until client cancels.
+	// Failed polls are reported in SummaryUpdate.error and do not end the stream.
+	WatchSummary(ctx context.Context, in *WatchSummaryRequest, opts ...grpc.CallOption) (MinerService_WatchSummaryClient, error)
+}
+
+type minerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMinerServiceClient(cc grpc.ClientConnInterface) MinerServiceClient {
+	return &minerServiceClient{cc}
+}
+
+func (c *minerServiceClient) Version(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*VersionResponse, error) {
+	out := new(VersionResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/Version", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) Summary(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*SummaryResponse, error) {
+	out := new(SummaryResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/Summary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) Devs(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*DevsResponse, error) {
+	out := new(DevsResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/Devs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) Pools(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*PoolsResponse, error) {
+	out := new(PoolsResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/Pools", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) Stats(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) DevDetails(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*DevDetailsResponse, error) {
+	out := new(DevDetailsResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/DevDetails", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) AddPool(ctx context.Context, in *AddPoolRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/AddPool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) EnablePool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/EnablePool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) DisablePool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/DisablePool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) SwitchPool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/SwitchPool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) RemovePool(ctx context.Context, in *PoolRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/RemovePool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) Restart(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/Restart", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) Quit(ctx context.Context, in *MinerRequest, opts ...grpc.CallOption) (*CommandResponse, error) {
+	out := new(CommandResponse)
+	err := c.cc.Invoke(ctx, "/trm.miner.v1.MinerService/Quit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *minerServiceClient) WatchSummary(ctx context.Context, in *WatchSummaryRequest, opts ...grpc.CallOption) (MinerService_WatchSummaryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MinerService_ServiceDesc.Streams[0], "/trm.miner.v1.MinerService/WatchSummary", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &minerServiceWatchSummaryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MinerService_WatchSummaryClient interface {
+	Recv() (*SummaryUpdate, error)
+	grpc.ClientStream
+}
+
+type minerServiceWatchSummaryClient struct {
+	grpc.ClientStream
+}
+
+func (x *minerServiceWatchSummaryClient) Recv() (*SummaryUpdate, error) {
+	m := new(SummaryUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MinerServiceServer is the server API for MinerService service.
+// All implementations must embed UnimplementedMinerServiceServer
+// for forward compatibility
+type MinerServiceServer interface {
+	Version(context.Context, *MinerRequest) (*VersionResponse, error)
+	Summary(context.Context, *MinerRequest) (*SummaryResponse, error)
+	Devs(context.Context, *MinerRequest) (*DevsResponse, error)
+	Pools(context.Context, *MinerRequest) (*PoolsResponse, error)
+	Stats(context.Context, *MinerRequest) (*StatsResponse, error)
+	DevDetails(context.Context, *MinerRequest) (*DevDetailsResponse, error)
+	AddPool(context.Context, *AddPoolRequest) (*CommandResponse, error)
+	EnablePool(context.Context, *PoolRequest) (*CommandResponse, error)
+	DisablePool(context.Context, *PoolRequest) (*CommandResponse, error)
+	SwitchPool(context.Context, *PoolRequest) (*CommandResponse, error)
+	RemovePool(context.Context, *PoolRequest) (*CommandResponse, error)
+	Restart(context.Context, *MinerRequest) (*CommandResponse, error)
+	Quit(context.Context, *MinerRequest) (*CommandResponse, error)
+	// WatchSummary polls miner summary and streams it until client cancels.
+	// Failed polls are reported in SummaryUpdate.error and do not end the stream.
+	WatchSummary(*WatchSummaryRequest, MinerService_WatchSummaryServer) error
+	mustEmbedUnimplementedMinerServiceServer()
+}
+
+// UnimplementedMinerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMinerServiceServer struct {
+}
+
+func (UnimplementedMinerServiceServer) Version(context.Context, *MinerRequest) (*VersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+func (UnimplementedMinerServiceServer) Summary(context.Context, *MinerRequest) (*SummaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Summary not implemented")
+}
+func (UnimplementedMinerServiceServer) Devs(context.Context, *MinerRequest) (*DevsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Devs not implemented")
+}
+func (UnimplementedMinerServiceServer) Pools(context.Context, *MinerRequest) (*PoolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pools not implemented")
+}
+func (UnimplementedMinerServiceServer) Stats(context.Context, *MinerRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (UnimplementedMinerServiceServer) DevDetails(context.Context, *MinerRequest) (*DevDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DevDetails not implemented")
+}
+func (UnimplementedMinerServiceServer) AddPool(context.Context, *AddPoolRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPool not implemented")
+}
+func (UnimplementedMinerServiceServer) EnablePool(context.Context, *PoolRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnablePool not implemented")
+}
+func (UnimplementedMinerServiceServer) DisablePool(context.Context, *PoolRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisablePool not implemented")
+}
+func (UnimplementedMinerServiceServer) SwitchPool(context.Context, *PoolRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwitchPool not implemented")
+}
+func (UnimplementedMinerServiceServer) RemovePool(context.Context, *PoolRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePool not implemented")
+}
+func (UnimplementedMinerServiceServer) Restart(context.Context, *MinerRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restart not implemented")
+}
+func (UnimplementedMinerServiceServer) Quit(context.Context, *MinerRequest) (*CommandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Quit not implemented")
+}
+func (UnimplementedMinerServiceServer) WatchSummary(*WatchSummaryRequest, MinerService_WatchSummaryServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSummary not implemented")
+}
+func (UnimplementedMinerServiceServer) mustEmbedUnimplementedMinerServiceServer() {}
+
+// UnsafeMinerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MinerServiceServer will
+// result in compilation errors.
+type UnsafeMinerServiceServer interface {
+	mustEmbedUnimplementedMinerServiceServer()
+}
+
+func RegisterMinerServiceServer(s grpc.ServiceRegistrar, srv MinerServiceServer) {
+	s.RegisterService(&MinerService_ServiceDesc, srv)
+}
+
+func _MinerService_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).Version(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_Summary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).Summary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/Summary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).Summary(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_Devs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).Devs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/Devs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).Devs(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_Pools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).Pools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/Pools",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).Pools(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).Stats(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_DevDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).DevDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/DevDetails",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).DevDetails(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_AddPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).AddPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/AddPool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).AddPool(ctx, req.(*AddPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_EnablePool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).EnablePool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/EnablePool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).EnablePool(ctx, req.(*PoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_DisablePool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).DisablePool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/DisablePool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).DisablePool(ctx, req.(*PoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_SwitchPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).SwitchPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/SwitchPool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).SwitchPool(ctx, req.(*PoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_RemovePool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).RemovePool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/RemovePool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).RemovePool(ctx, req.(*PoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_Restart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).Restart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/Restart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).Restart(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_Quit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MinerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MinerServiceServer).Quit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trm.miner.v1.MinerService/Quit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MinerServiceServer).Quit(ctx, req.(*MinerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MinerService_WatchSummary_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSummaryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MinerServiceServer).WatchSummary(m, &minerServiceWatchSummaryServer{stream})
+}
+
+type MinerService_WatchSummaryServer interface {
+	Send(*SummaryUpdate) error
+	grpc.ServerStream
+}
+
+type minerServiceWatchSummaryServer struct {
+	grpc.ServerStream
+}
+
+func (x *minerServiceWatchSummaryServer) Send(m *SummaryUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// MinerService_ServiceDesc is the grpc.ServiceDesc for MinerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MinerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trm.miner.v1.MinerService",
+	HandlerType: (*MinerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Version",
+			Handler:    _MinerService_Version_Handler,
+		},
+		{
+			MethodName: "Summary",
+			Handler:    _MinerService_Summary_Handler,
+		},
+		{
+			MethodName: "Devs",
+			Handler:    _MinerService_Devs_Handler,
+		},
+		{
+			MethodName: "Pools",
+			Handler:    _MinerService_Pools_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _MinerService_Stats_Handler,
+		},
+		{
+			MethodName: "DevDetails",
+			Handler:    _MinerService_DevDetails_Handler,
+		},
+		{
+			MethodName: "AddPool",
+			Handler:    _MinerService_AddPool_Handler,
+		},
+		{
+			MethodName: "EnablePool",
+			Handler:    _MinerService_EnablePool_Handler,
+		},
+		{
+			MethodName: "DisablePool",
+			Handler:    _MinerService_DisablePool_Handler,
+		},
+		{
+			MethodName: "SwitchPool",
+			Handler:    _MinerService_SwitchPool_Handler,
+		},
+		{
+			MethodName: "RemovePool",
+			Handler:    _MinerService_RemovePool_Handler,
+		},
+		{
+			MethodName: "Restart",
+			Handler:    _MinerService_Restart_Handler,
+		},
+		{
+			MethodName: "Quit",
+			Handler:    _MinerService_Quit_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSummary",
+			Handler:       _MinerService_WatchSummary_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "miner.proto",
+}
@@ -0,0 +1,362 @@
+// Package grpcapi serves miners over gRPC MinerService, see package minerpb
+// for service definition.
+//
+// Usage:
+//
+//	srv := grpcapi.NewServer(miners)
+//	srv.Tokens = map[string][]grpcapi.Scope{
+//		"reader-token": {grpcapi.ReadScope},
+//		"admin-token":  {grpcapi.ReadScope, grpcapi.WriteScope},
+//	}
+//	gs := grpc.NewServer(
+//		grpc.UnaryInterceptor(srv.UnaryInterceptor),
+//		grpc.StreamInterceptor(srv.StreamInterceptor),
+//	)
+//	minerpb.RegisterMinerServiceServer(gs, srv)
+//	gs.Serve(lis)
+//
+// Clients pass token as "authorization: Bearer <token>" metadata.
+// Pool and restart/quit methods require write scope, others read scope.
+// Tokens are checked by interceptors only, server registered without them
+// serves everyone. Miners in read-only mode reject write methods with
+// PermissionDenied regardless of token scope.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"github.com/sokdak/go-teamredminer-api/grpcapi/minerpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultWatchInterval is WatchSummary poll interval used when
+// request doesn't specify one
+const DefaultWatchInterval = 10 * time.Second
+
+// MinWatchInterval is minimal WatchSummary poll interval,
+// shorter requested intervals are raised to it
+const MinWatchInterval = time.Second
+
+// Server implements minerpb.MinerServiceServer by proxying calls to miners.
+type Server struct {
+	minerpb.UnimplementedMinerServiceServer
+
+	// Timeout limits single miner call, zero means no limit
+	// besides miner own timeout.
+	Timeout time.Duration
+
+	// WatchInterval is default WatchSummary poll interval,
+	// DefaultWatchInterval is used if zero.
+	WatchInterval time.Duration
+
+	// Tokens maps bearer token to allowed scopes, checked by
+	// UnaryInterceptor and StreamInterceptor.
+	// Authorization is disabled if Tokens is nil.
+	Tokens map[string][]Scope
+
+	mu     sync.RWMutex
+	miners map[string]*cgminer.CGMiner
+}
+
+// NewServer returns service for passed miners keyed by miner ID
+func NewServer(miners map[string]*cgminer.CGMiner) *Server {
+	m := make(map[string]*cgminer.CGMiner, len(miners))
+	for id, miner := range miners {
+		m[id] = miner
+	}
+	return &Server{miners: m}
+}
+
+// SetMiner adds or replaces miner
+func (s *Server) SetMiner(id string, miner *cgminer.CGMiner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.miners[id] = miner
+}
+
+// RemoveMiner removes miner
+func (s *Server) RemoveMiner(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.miners, id)
+}
+
+// miner returns miner by ID. Empty ID selects the only miner
+// if server has single one.
+func (s *Server) miner(id string) (*cgminer.CGMiner, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id == "" {
+		if len(s.miners) == 1 {
+			for _, m := range s.miners {
+				return m, nil
+			}
+		}
+		return nil, status.Error(codes.InvalidArgument, "miner is required")
+	}
+
+	m, ok := s.miners[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown miner %q", id)
+	}
+	return m, nil
+}
+
+func (s *Server) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.Timeout > 0 {
+		return context.WithTimeout(ctx, s.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// call resolves miner and calls fn with request context
+func (s *Server) call(ctx context.Context, id string, fn func(ctx context.Context, miner *cgminer.CGMiner) error) error {
+	miner, err := s.miner(id)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := s.context(ctx)
+	defer cancel()
+	if err := fn(ctx, miner); err != nil {
+		return minerError(err)
+	}
+	return nil
+}
+
+// minerError maps miner call error to gRPC status
+func minerError(err error) error {
+	var connErr cgminer.ConnectError
+	switch {
+	case errors.Is(err, cgminer.ErrReadOnly):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, cgminer.ErrRateLimited):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.As(err, &connErr):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Unknown, err.Error())
+	}
+}
+
+// Version implements minerpb.MinerServiceServer
+func (s *Server) Version(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.VersionResponse, error) {
+	var resp *minerpb.VersionResponse
+	err := s.call(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		v, err := miner.VersionContext(ctx)
+		if err != nil {
+			return err
+		}
+		resp = versionProto(v)
+		return nil
+	})
+	return resp, err
+}
+
+// Summary implements minerpb.MinerServiceServer
+func (s *Server) Summary(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.SummaryResponse, error) {
+	var resp *minerpb.SummaryResponse
+	err := s.call(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		summary, err := miner.SummaryContext(ctx)
+		if err != nil {
+			return err
+		}
+		resp = &minerpb.SummaryResponse{Summary: summaryProto(summary)}
+		return nil
+	})
+	return resp, err
+}
+
+// Devs implements minerpb.MinerServiceServer
+func (s *Server) Devs(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.DevsResponse, error) {
+	var resp *minerpb.DevsResponse
+	err := s.call(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		devs, err := miner.DevsContext(ctx)
+		if err != nil {
+			return err
+		}
+		resp = &minerpb.DevsResponse{Devs: make([]*minerpb.Device, 0, len(*devs))}
+		for i := range *devs {
+			resp.Devs = append(resp.Devs, deviceProto(&(*devs)[i]))
+		}
+		return nil
+	})
+	return resp, err
+}
+
+// Pools implements minerpb.MinerServiceServer
+func (s *Server) Pools(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.PoolsResponse, error) {
+	var resp *minerpb.PoolsResponse
+	err := s.call(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		pools, err := miner.PoolsContext(ctx)
+		if err != nil {
+			return err
+		}
+		resp = &minerpb.PoolsResponse{Pools: make([]*minerpb.Pool, 0, len(pools))}
+		for i := range pools {
+			resp.Pools = append(resp.Pools, poolProto(&pools[i]))
+		}
+		return nil
+	})
+	return resp, err
+}
+
+// Stats implements minerpb.MinerServiceServer.
+//
+// Stats records are returned as is, since their fields depend on miner vendor.
+func (s *Server) Stats(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.StatsResponse, error) {
+	var resp *minerpb.StatsResponse
+	err := s.call(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		stats, err := miner.CallMap(ctx, cgminer.NewCommandWithoutParameter("stats"))
+		if err != nil {
+			return err
+		}
+		resp = statsProto(stats.Section("STATS"))
+		return nil
+	})
+	return resp, err
+}
+
+// DevDetails implements minerpb.MinerServiceServer
+func (s *Server) DevDetails(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.DevDetailsResponse, error) {
+	var resp *minerpb.DevDetailsResponse
+	err := s.call(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		details, err := miner.DevDetailContext(ctx)
+		if err != nil {
+			return err
+		}
+		resp = &minerpb.DevDetailsResponse{Details: make([]*minerpb.DeviceDetail, 0, len(details))}
+		for _, d := range details {
+			resp.Details = append(resp.Details, &minerpb.DeviceDetail{
+				Id:         int64(d.Id),
+				Model:      d.Model,
+				Kernel:     d.Kernel,
+				DevicePath: d.DevicePath,
+			})
+		}
+		return nil
+	})
+	return resp, err
+}
+
+// AddPool implements minerpb.MinerServiceServer
+func (s *Server) AddPool(ctx context.Context, req *minerpb.AddPoolRequest) (*minerpb.CommandResponse, error) {
+	if req.GetUrl() == "" {
+		return nil, status.Error(codes.InvalidArgument, "url is required")
+	}
+	return s.command(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		return miner.AddPoolContext(ctx, req.GetUrl(), req.GetUser(), req.GetPassword())
+	})
+}
+
+// EnablePool implements minerpb.MinerServiceServer
+func (s *Server) EnablePool(ctx context.Context, req *minerpb.PoolRequest) (*minerpb.CommandResponse, error) {
+	return s.command(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		return miner.EnablePoolContext(ctx, &cgminer.Pool{Pool: req.GetPool()})
+	})
+}
+
+// DisablePool implements minerpb.MinerServiceServer
+func (s *Server) DisablePool(ctx context.Context, req *minerpb.PoolRequest) (*minerpb.CommandResponse, error) {
+	return s.command(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		return miner.DisablePoolContext(ctx, &cgminer.Pool{Pool: req.GetPool()})
+	})
+}
+
+// SwitchPool implements minerpb.MinerServiceServer
+func (s *Server) SwitchPool(ctx context.Context, req *minerpb.PoolRequest) (*minerpb.CommandResponse, error) {
+	return s.command(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		return miner.SwitchPoolContext(ctx, &cgminer.Pool{Pool: req.GetPool()})
+	})
+}
+
+// RemovePool implements minerpb.MinerServiceServer
+func (s *Server) RemovePool(ctx context.Context, req *minerpb.PoolRequest) (*minerpb.CommandResponse, error) {
+	return s.command(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		return miner.RemovePoolContext(ctx, &cgminer.Pool{Pool: req.GetPool()})
+	})
+}
+
+// Restart implements minerpb.MinerServiceServer
+func (s *Server) Restart(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.CommandResponse, error) {
+	return s.command(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		return miner.RestartContext(ctx)
+	})
+}
+
+// Quit implements minerpb.MinerServiceServer
+func (s *Server) Quit(ctx context.Context, req *minerpb.MinerRequest) (*minerpb.CommandResponse, error) {
+	return s.command(ctx, req.GetMiner(), func(ctx context.Context, miner *cgminer.CGMiner) error {
+		return miner.QuitContext(ctx)
+	})
+}
+
+func (s *Server) command(ctx context.Context, id string, fn func(ctx context.Context, miner *cgminer.CGMiner) error) (*minerpb.CommandResponse, error) {
+	if err := s.call(ctx, id, fn); err != nil {
+		return nil, err
+	}
+	return &minerpb.CommandResponse{}, nil
+}
+
+// WatchSummary implements minerpb.MinerServiceServer.
+//
+// Summary is polled immediately and then every interval until client
+// cancels the stream. Failed polls are sent as updates with error.
+func (s *Server) WatchSummary(req *minerpb.WatchSummaryRequest, stream minerpb.MinerService_WatchSummaryServer) error {
+	miner, err := s.miner(req.GetMiner())
+	if err != nil {
+		return err
+	}
+
+	interval := s.WatchInterval
+	if req.GetInterval() != nil {
+		interval = req.GetInterval().AsDuration()
+	}
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	if interval < MinWatchInterval {
+		interval = MinWatchInterval
+	}
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := stream.Send(s.pollSummary(ctx, req.GetMiner(), miner)); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) pollSummary(ctx context.Context, id string, miner *cgminer.CGMiner) *minerpb.SummaryUpdate {
+	ctx, cancel := s.context(ctx)
+	defer cancel()
+
+	summary, err := miner.SummaryContext(ctx)
+	update := &minerpb.SummaryUpdate{Miner: id, Time: timestamppb.Now()}
+	if err != nil {
+		update.Error = err.Error()
+		return update
+	}
+	update.Summary = summaryProto(summary)
+	return update
+}
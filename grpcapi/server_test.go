@@ -0,0 +1,394 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"github.com/sokdak/go-teamredminer-api/grpcapi/minerpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// fakeMiner answers API commands through in-memory connections
+type fakeMiner struct {
+	mu       sync.Mutex
+	commands []string
+	down     bool
+}
+
+func (f *fakeMiner) miner() *cgminer.CGMiner {
+	m := cgminer.NewCGMiner("10.0.0.1", 4028, 5*time.Second)
+	m.Dialer = cgminer.DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		f.mu.Lock()
+		down := f.down
+		f.mu.Unlock()
+		if down {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+		}
+
+		client, server := net.Pipe()
+		go f.serve(server)
+		return client, nil
+	})
+	return m
+}
+
+func (f *fakeMiner) serve(conn net.Conn) {
+	defer conn.Close()
+	var cmd cgminer.Command
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	f.commands = append(f.commands, cmd.Command+"|"+cmd.Parameter)
+	f.mu.Unlock()
+
+	status := `"STATUS":[{"STATUS":"S","Code":1}],"id":1`
+	var rsp string
+	switch cmd.Command {
+	case "version":
+		rsp = `{` + status + `,"VERSION":[{"CGMiner":"4.0","API":"3.7","Miner":"TeamRedMiner 0.10"}]}`
+	case "summary":
+		rsp = `{` + status + `,"SUMMARY":[{"Elapsed":100,"MHS av":12.5,"Accepted":7}]}`
+	case "devs":
+		rsp = `{` + status + `,"DEVS":[{"GPU":0,"MHS av":6.25},{"GPU":1,"MHS av":6.25}]}`
+	case "pools":
+		rsp = `{` + status + `,"POOLS":[{"POOL":0,"URL":"stratum+tcp://a:3333","Best Share":"1.5"}]}`
+	case "stats":
+		rsp = `{` + status + `,"STATS":[{"ID":"GPU0","Elapsed":100,"Name":"trm"}]}`
+	case "devdetails":
+		rsp = `{` + status + `,"DEVDETAILS":[{"ID":0,"Model":"RX 580"}]}`
+	case "removepool":
+		rsp = `{"STATUS":[{"STATUS":"E","Code":66,"Msg":"Cannot remove active pool"}],"id":1}`
+	default:
+		rsp = `{` + status + `}`
+	}
+	_, _ = conn.Write(append([]byte(rsp), 0x00))
+}
+
+func (f *fakeMiner) calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+func newTestClient(t *testing.T, s *Server, opts ...grpc.ServerOption) minerpb.MinerServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer(opts...)
+	minerpb.RegisterMinerServiceServer(gs, s)
+	go func() { _ = gs.Serve(lis) }()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		gs.Stop()
+	})
+	return minerpb.NewMinerServiceClient(conn)
+}
+
+func TestServer_Read(t *testing.T) {
+	fake := &fakeMiner{}
+	client := newTestClient(t, NewServer(map[string]*cgminer.CGMiner{"rig1": fake.miner()}))
+	ctx := context.Background()
+	req := &minerpb.MinerRequest{Miner: "rig1"}
+
+	version, err := client.Version(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.Miner != "TeamRedMiner 0.10" || version.Api != "3.7" {
+		t.Errorf("unexpected version: %v", version)
+	}
+
+	summary, err := client.Summary(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Summary.MhsAv != 12.5 || summary.Summary.Elapsed != 100 || summary.Summary.Accepted != 7 {
+		t.Errorf("unexpected summary: %v", summary.Summary)
+	}
+
+	devs, err := client.Devs(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devs.Devs) != 2 || devs.Devs[1].Gpu != 1 || devs.Devs[1].MhsAv != 6.25 {
+		t.Errorf("unexpected devs: %v", devs.Devs)
+	}
+
+	pools, err := client.Pools(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools.Pools) != 1 || pools.Pools[0].Url != "stratum+tcp://a:3333" || pools.Pools[0].BestShare != 1.5 {
+		t.Errorf("unexpected pools: %v", pools.Pools)
+	}
+
+	stats, err := client.Stats(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Records) != 1 || stats.Records[0].Fields["ID"] != "GPU0" || stats.Records[0].Fields["Elapsed"] != "100" {
+		t.Errorf("unexpected stats: %v", stats.Records)
+	}
+
+	details, err := client.DevDetails(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Details) != 1 || details.Details[0].Model != "RX 580" {
+		t.Errorf("unexpected details: %v", details.Details)
+	}
+}
+
+func TestServer_Commands(t *testing.T) {
+	fake := &fakeMiner{}
+	client := newTestClient(t, NewServer(map[string]*cgminer.CGMiner{"rig1": fake.miner()}))
+	ctx := context.Background()
+
+	// empty miner ID selects the only miner
+	calls := []func() error{
+		func() error {
+			_, err := client.AddPool(ctx, &minerpb.AddPoolRequest{Url: "stratum+tcp://b:3333", User: "u", Password: "x"})
+			return err
+		},
+		func() error { _, err := client.EnablePool(ctx, &minerpb.PoolRequest{Pool: 1}); return err },
+		func() error { _, err := client.DisablePool(ctx, &minerpb.PoolRequest{Pool: 1}); return err },
+		func() error { _, err := client.SwitchPool(ctx, &minerpb.PoolRequest{Pool: 1}); return err },
+		func() error { _, err := client.Restart(ctx, &minerpb.MinerRequest{}); return err },
+		func() error { _, err := client.Quit(ctx, &minerpb.MinerRequest{}); return err },
+	}
+	for i, call := range calls {
+		if err := call(); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+
+	want := []string{
+		"addpool|stratum+tcp://b:3333,u,x",
+		"enablepool|1",
+		"disablepool|1",
+		"switchpool|1",
+		"restart|",
+		"quit|",
+	}
+	got := fake.calls()
+	if len(got) != len(want) {
+		t.Fatalf("want commands %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command %d: want %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	fake := &fakeMiner{}
+	readOnly := fake.miner()
+	readOnly.ReadOnly = true
+	down := &fakeMiner{down: true}
+
+	client := newTestClient(t, NewServer(map[string]*cgminer.CGMiner{
+		"rig1": fake.miner(),
+		"ro":   readOnly,
+		"down": down.miner(),
+	}))
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"unknown miner", func() error {
+			_, err := client.Summary(ctx, &minerpb.MinerRequest{Miner: "nope"})
+			return err
+		}, codes.NotFound},
+		{"ambiguous miner", func() error {
+			_, err := client.Summary(ctx, &minerpb.MinerRequest{})
+			return err
+		}, codes.InvalidArgument},
+		{"missing url", func() error {
+			_, err := client.AddPool(ctx, &minerpb.AddPoolRequest{Miner: "rig1"})
+			return err
+		}, codes.InvalidArgument},
+		{"read only", func() error {
+			_, err := client.Restart(ctx, &minerpb.MinerRequest{Miner: "ro"})
+			return err
+		}, codes.PermissionDenied},
+		{"unreachable", func() error {
+			_, err := client.Summary(ctx, &minerpb.MinerRequest{Miner: "down"})
+			return err
+		}, codes.Unavailable},
+		{"api error", func() error {
+			_, err := client.RemovePool(ctx, &minerpb.PoolRequest{Miner: "rig1", Pool: 0})
+			return err
+		}, codes.Unknown},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if code := status.Code(c.call()); code != c.code {
+				t.Errorf("want %s, got %s", c.code, code)
+			}
+		})
+	}
+}
+
+func TestServer_RateLimited(t *testing.T) {
+	fake := &fakeMiner{}
+	miner := fake.miner()
+	miner.Limiter = cgminer.NewLimiter(cgminer.Rate{Limit: 0.001, Burst: 1}, cgminer.DefaultWriteRate)
+	srv := NewServer(map[string]*cgminer.CGMiner{"rig1": miner})
+	srv.Timeout = time.Second
+	client := newTestClient(t, srv)
+	req := &minerpb.MinerRequest{Miner: "rig1"}
+
+	if _, err := client.Summary(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Devs(context.Background(), req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want ResourceExhausted, got %v", err)
+	}
+}
+
+func TestServer_WatchSummary(t *testing.T) {
+	fake := &fakeMiner{}
+	client := newTestClient(t, NewServer(map[string]*cgminer.CGMiner{"rig1": fake.miner()}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchSummary(ctx, &minerpb.WatchSummaryRequest{
+		Miner:    "rig1",
+		Interval: durationpb.New(time.Millisecond),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Miner != "rig1" || update.Error != "" || update.Summary.GetMhsAv() != 12.5 || update.Time == nil {
+		t.Errorf("unexpected update: %v", update)
+	}
+
+	fake.mu.Lock()
+	fake.down = true
+	fake.mu.Unlock()
+
+	// interval is raised to MinWatchInterval
+	update, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Error == "" || update.Summary != nil {
+		t.Errorf("expected failed poll, got %v", update)
+	}
+	if d := update.Time.AsTime().Sub(time.Now()); d > 0 {
+		t.Errorf("update time is in future: %s", d)
+	}
+
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("want Canceled after cancel, got %v", err)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	fake := &fakeMiner{}
+	srv := NewServer(map[string]*cgminer.CGMiner{"rig1": fake.miner()})
+	srv.Tokens = map[string][]Scope{
+		"reader": {ReadScope},
+		"admin":  {ReadScope, WriteScope},
+	}
+	client := newTestClient(t, srv,
+		grpc.UnaryInterceptor(srv.UnaryInterceptor),
+		grpc.StreamInterceptor(srv.StreamInterceptor))
+	req := &minerpb.MinerRequest{Miner: "rig1"}
+
+	withToken := func(auth string) context.Context {
+		if auth == "" {
+			return context.Background()
+		}
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", auth)
+	}
+
+	cases := []struct {
+		name string
+		auth string
+		call func(ctx context.Context) error
+		code codes.Code
+	}{
+		{"no token", "", func(ctx context.Context) error {
+			_, err := client.Summary(ctx, req)
+			return err
+		}, codes.Unauthenticated},
+		{"invalid token", "Bearer nope", func(ctx context.Context) error {
+			_, err := client.Summary(ctx, req)
+			return err
+		}, codes.Unauthenticated},
+		{"read", "bearer reader", func(ctx context.Context) error {
+			_, err := client.Summary(ctx, req)
+			return err
+		}, codes.OK},
+		{"write without scope", "Bearer reader", func(ctx context.Context) error {
+			_, err := client.Restart(ctx, req)
+			return err
+		}, codes.PermissionDenied},
+		{"write", "Bearer admin", func(ctx context.Context) error {
+			_, err := client.SwitchPool(ctx, &minerpb.PoolRequest{Miner: "rig1", Pool: 1})
+			return err
+		}, codes.OK},
+		{"stream without token", "", func(ctx context.Context) error {
+			stream, err := client.WatchSummary(ctx, &minerpb.WatchSummaryRequest{Miner: "rig1"})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.Unauthenticated},
+		{"stream", "Bearer reader", func(ctx context.Context) error {
+			stream, err := client.WatchSummary(ctx, &minerpb.WatchSummaryRequest{Miner: "rig1"})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.OK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(withToken(c.auth), 5*time.Second)
+			defer cancel()
+			if code := status.Code(c.call(ctx)); code != c.code {
+				t.Errorf("want %s, got %s", c.code, code)
+			}
+		})
+	}
+
+	for _, cmd := range fake.calls() {
+		if cmd == "restart|" {
+			t.Error("restart reached miner without write scope")
+		}
+	}
+}
@@ -0,0 +1,761 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// ErrCorrupted is returned when segment record checksum doesn't match
+var ErrCorrupted = errors.New("segment record is corrupted")
+
+// ErrClosed is returned when DiskStore is used after Close
+var ErrClosed = errors.New("disk store is closed")
+
+// ErrInvalidMiner is returned for miner names which can't be used
+// as directory name
+var ErrInvalidMiner = errors.New("invalid miner name")
+
+const (
+	segmentMagic = "TRMSEG1\n"
+
+	// rawExt is extension of segments written by Append
+	rawExt = ".seg"
+
+	// compactExt is extension of downsampled segments written by Compact
+	compactExt = ".cseg"
+
+	// recordHeaderSize is size of record length and CRC
+	recordHeaderSize = 8
+
+	// maxRecordSize limits record size to detect garbage lengths
+	maxRecordSize = 1 << 20
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Record is single persisted sample
+type Record struct {
+	Time   time.Time
+	Source string
+	Values map[string]float64
+
+	// Counts is number of samples averaged into each value
+	// of downsampled record, nil for raw records
+	Counts map[string]uint32
+}
+
+// DiskOptions configures DiskStore
+type DiskOptions struct {
+	// SegmentDuration is time span covered by single segment file.
+	// Default is 1 hour.
+	SegmentDuration time.Duration
+
+	// Retention is how long segments are kept. Zero keeps segments forever.
+	Retention time.Duration
+
+	// CompactAfter is age after which raw segments are downsampled.
+	// Zero disables compaction.
+	CompactAfter time.Duration
+
+	// CompactWidth is bucket width of downsampled segments.
+	// Default is 5 minutes.
+	CompactWidth time.Duration
+
+	// Sync makes each append to be flushed to disk
+	Sync bool
+}
+
+// DiskStore is append-only on-disk store of samples.
+//
+// Each miner has own directory with segment files, one per SegmentDuration.
+// Records are length-prefixed and protected by CRC-32C, so partially
+// written records left after crash are detected and dropped.
+//
+// DiskStore is safe for concurrent use.
+type DiskStore struct {
+	dir  string
+	opts DiskOptions
+
+	mu      sync.Mutex
+	writers map[string]*segmentWriter
+	closed  bool
+}
+
+type segmentWriter struct {
+	start time.Time
+	file  *os.File
+}
+
+// OpenDisk opens or creates store in directory
+func OpenDisk(dir string, opts DiskOptions) (*DiskStore, error) {
+	if opts.SegmentDuration <= 0 {
+		opts.SegmentDuration = time.Hour
+	}
+	if opts.CompactWidth <= 0 {
+		opts.CompactWidth = 5 * time.Minute
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskStore{dir: dir, opts: opts, writers: make(map[string]*segmentWriter)}, nil
+}
+
+// Append writes record to miner segment.
+//
+// Records may be appended out of order, e.g. when backfilling
+// data collected while store was unavailable.
+func (d *DiskStore) Append(miner string, rec Record) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+
+	w, err := d.writer(miner, rec.Time.Truncate(d.opts.SegmentDuration))
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.file.Write(encodeRecord(rec)); err != nil {
+		return err
+	}
+	if d.opts.Sync {
+		return w.file.Sync()
+	}
+	return nil
+}
+
+// AppendSummary writes Summary record
+func (d *DiskStore) AppendSummary(miner string, t time.Time, sum cgminer.Summary) error {
+	return d.Append(miner, rowRecord(t, SummarySource, SummaryFields, summaryRow(sum)))
+}
+
+// AppendDevs writes record for each device
+func (d *DiskStore) AppendDevs(miner string, t time.Time, devs []cgminer.Devs) error {
+	for _, dev := range devs {
+		if err := d.Append(miner, rowRecord(t, DeviceSource(dev.GPU), DeviceFields, deviceRow(dev))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendPools writes record for each pool
+func (d *DiskStore) AppendPools(miner string, t time.Time, pools []cgminer.Pool) error {
+	for _, p := range pools {
+		if err := d.Append(miner, rowRecord(t, PoolSource(p.Pool), PoolFields, poolRow(p))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rowRecord(t time.Time, source string, fields []string, row []float64) Record {
+	values := make(map[string]float64, len(fields))
+	for i, f := range fields {
+		values[f] = row[i]
+	}
+	return Record{Time: t, Source: source, Values: values}
+}
+
+// writer returns writer of miner segment, switching segment if needed
+func (d *DiskStore) writer(miner string, start time.Time) (*segmentWriter, error) {
+	if w, ok := d.writers[miner]; ok {
+		if w.start.Equal(start) {
+			return w, nil
+		}
+		_ = w.file.Close()
+		delete(d.writers, miner)
+	}
+
+	dir, err := d.minerDir(miner)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, segmentName(start, rawExt))
+	f, err := openSegment(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &segmentWriter{start: start, file: f}
+	d.writers[miner] = w
+	return w, nil
+}
+
+// openSegment opens segment for appending, writing header to new file
+// and dropping partially written record at the end of existing one.
+//
+// Segment which can't be scanned to the end is moved aside and new one
+// is started, so unreadable data is never overwritten.
+func openSegment(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	size, valid, err := scanSegment(f, nil)
+	var corrupted *corruptionError
+	switch {
+	case err == nil:
+	case errors.As(err, &corrupted) && corrupted.unreadable && valid > 0:
+		f.Close()
+		if err := os.Rename(path, fmt.Sprintf("%s.%d.corrupted", path, time.Now().UnixNano())); err != nil {
+			return nil, err
+		}
+		return openSegment(path)
+	case errors.Is(err, ErrCorrupted) && valid > 0:
+		// bad records are skipped by readers, keep appending
+	default:
+		// refuse to overwrite file which is not a segment
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	err = nil
+	if valid < size {
+		// drop partially written record
+		err = f.Truncate(valid)
+	}
+	if err == nil {
+		_, err = f.Seek(valid, io.SeekStart)
+	}
+	if err == nil && valid == 0 {
+		_, err = f.Write([]byte(segmentMagic))
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// ReadRange returns miner records within [from, to) sorted by time.
+//
+// Downsampled records are returned for compacted periods.
+// Corrupted records are skipped, the rest are returned along with
+// error wrapping ErrCorrupted.
+func (d *DiskStore) ReadRange(miner string, from, to time.Time) ([]Record, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	segments, err := d.segments(miner)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		records []Record
+		readErr error
+	)
+	for _, seg := range segments {
+		if !seg.start.Before(to) || !seg.start.Add(d.opts.SegmentDuration).After(from) {
+			continue
+		}
+
+		err := readSegment(seg.path, func(rec Record) {
+			if !rec.Time.Before(from) && rec.Time.Before(to) {
+				records = append(records, rec)
+			}
+		})
+		if err != nil && readErr == nil {
+			readErr = err
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, readErr
+}
+
+// Miners returns list of miners which have stored data
+func (d *DiskStore) Miners() ([]string, error) {
+	entries, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var miners []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		// directories not created by store, e.g. "%2E%2E", are skipped
+		if name, err := url.QueryUnescape(e.Name()); err == nil && url.QueryEscape(name) == e.Name() {
+			miners = append(miners, name)
+		}
+	}
+	return miners, nil
+}
+
+// Compact removes segments older than retention and downsamples
+// raw segments older than CompactAfter.
+//
+// Segments with corrupted records are not compacted, error wrapping
+// ErrCorrupted is returned after other segments are processed.
+func (d *DiskStore) Compact(now time.Time) error {
+	miners, err := d.Miners()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+
+	var corrupted error
+	for _, miner := range miners {
+		err := d.compactMiner(miner, now)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrCorrupted):
+			if corrupted == nil {
+				corrupted = fmt.Errorf("%s: %w", miner, err)
+			}
+		default:
+			return fmt.Errorf("%s: %w", miner, err)
+		}
+	}
+	return corrupted
+}
+
+func (d *DiskStore) compactMiner(miner string, now time.Time) error {
+	segments, err := d.segments(miner)
+	if err != nil {
+		return err
+	}
+
+	var corrupted error
+	for _, seg := range segments {
+		end := seg.start.Add(d.opts.SegmentDuration)
+		if w, ok := d.writers[miner]; ok && w.start.Equal(seg.start) && end.Before(now) {
+			// segment is complete, no need to keep it open
+			_ = w.file.Close()
+			delete(d.writers, miner)
+		}
+
+		switch {
+		case d.opts.Retention > 0 && !end.After(now.Add(-d.opts.Retention)):
+			if err := os.Remove(seg.path); err != nil {
+				return err
+			}
+		case d.opts.CompactAfter > 0 && !seg.compacted && !end.After(now.Add(-d.opts.CompactAfter)):
+			err := d.compactSegment(seg)
+			switch {
+			case err == nil:
+			case errors.Is(err, ErrCorrupted):
+				// keep compacting other segments
+				if corrupted == nil {
+					corrupted = err
+				}
+			default:
+				return err
+			}
+		}
+	}
+	return corrupted
+}
+
+// compactSegment replaces raw segment with downsampled one
+func (d *DiskStore) compactSegment(seg segment) error {
+	type bucketKey struct {
+		source string
+		start  int64
+	}
+	type bucket struct {
+		sums   map[string]float64
+		counts map[string]uint32
+	}
+
+	buckets := make(map[bucketKey]*bucket)
+	add := func(rec Record) {
+		key := bucketKey{source: rec.Source, start: rec.Time.Truncate(d.opts.CompactWidth).UnixNano()}
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{sums: make(map[string]float64), counts: make(map[string]uint32)}
+			buckets[key] = b
+		}
+		for f, v := range rec.Values {
+			if math.IsNaN(v) {
+				continue
+			}
+			n := uint32(1)
+			if c, ok := rec.Counts[f]; ok {
+				// downsampled value is weighted by its samples
+				n = c
+			}
+			b.sums[f] += v * float64(n)
+			b.counts[f] += n
+		}
+	}
+
+	// data backfilled after compaction is merged into existing segment.
+	// Segments with corrupted records are left as is, rewriting them
+	// would lose data which can't be read now.
+	path := strings.TrimSuffix(seg.path, rawExt) + compactExt
+	if _, err := os.Stat(path); err == nil {
+		if err := readSegment(path, add); err != nil {
+			return err
+		}
+	}
+	if err := readSegment(seg.path, add); err != nil {
+		return err
+	}
+
+	keys := make([]bucketKey, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].start != keys[j].start {
+			return keys[i].start < keys[j].start
+		}
+		return keys[i].source < keys[j].source
+	})
+
+	buf := bytes.NewBufferString(segmentMagic)
+	for _, k := range keys {
+		b := buckets[k]
+		values := make(map[string]float64, len(b.sums))
+		for f, sum := range b.sums {
+			values[f] = sum / float64(b.counts[f])
+		}
+		buf.Write(encodeRecord(Record{Time: time.Unix(0, k.start), Source: k.source, Values: values, Counts: b.counts}))
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return os.Remove(seg.path)
+}
+
+// Close closes open segment files
+func (d *DiskStore) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var err error
+	for miner, w := range d.writers {
+		if cerr := w.file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		delete(d.writers, miner)
+	}
+	d.closed = true
+	return err
+}
+
+// minerDir returns miner directory. Names which escape to "", "." or ".."
+// are rejected, as they would point to store or its parent directory.
+func (d *DiskStore) minerDir(miner string) (string, error) {
+	name := url.QueryEscape(miner)
+	if name == "" || name == "." || name == ".." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidMiner, miner)
+	}
+	return filepath.Join(d.dir, name), nil
+}
+
+type segment struct {
+	path      string
+	start     time.Time
+	compacted bool
+}
+
+// segments returns miner segments sorted by start time
+func (d *DiskStore) segments(miner string) ([]segment, error) {
+	dir, err := d.minerDir(miner)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []segment
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if ext != rawExt && ext != compactExt {
+			continue
+		}
+
+		sec, err := strconv.ParseInt(strings.TrimSuffix(e.Name(), ext), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment{
+			path:      filepath.Join(dir, e.Name()),
+			start:     time.Unix(sec, 0),
+			compacted: ext == compactExt,
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i].start.Before(segments[j].start) })
+	return segments, nil
+}
+
+func segmentName(start time.Time, ext string) string {
+	return fmt.Sprintf("%020d%s", start.Unix(), ext)
+}
+
+func readSegment(path string, fn func(Record)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, _, err := scanSegment(f, fn); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// corruptionError describes corrupted records found by scanSegment
+type corruptionError struct {
+	// bad is number of skipped records
+	bad int
+
+	// offset is offset of first corrupted record
+	offset int64
+
+	// reason is description of first corruption
+	reason string
+
+	// unreadable is set when scan can't continue past corrupted data
+	unreadable bool
+}
+
+func (err *corruptionError) Error() string {
+	msg := fmt.Sprintf("%s: %s at offset %d", ErrCorrupted, err.reason, err.offset)
+	if err.bad > 1 {
+		msg += fmt.Sprintf(" (%d bad records)", err.bad)
+	}
+	if err.unreadable {
+		msg += ", rest of segment is unreadable"
+	}
+	return msg
+}
+
+func (err *corruptionError) Is(target error) bool {
+	return target == ErrCorrupted
+}
+
+// scanSegment reads segment records and returns file size and length
+// of scanned data.
+//
+// Records with bad checksum are skipped and reported by error wrapping
+// ErrCorrupted after scan completes. Truncated record at the end of file
+// is not an error, it's left out of scanned data.
+func scanSegment(f *os.File, fn func(Record)) (size, valid int64, err error) {
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	size = int64(len(data))
+	if size == 0 {
+		return 0, 0, nil
+	}
+	if !bytes.HasPrefix(data, []byte(segmentMagic)) {
+		if size < int64(len(segmentMagic)) && bytes.HasPrefix([]byte(segmentMagic), data) {
+			// header was not fully written
+			return size, 0, nil
+		}
+		return size, 0, fmt.Errorf("%w: bad segment header", ErrCorrupted)
+	}
+
+	var corrupted *corruptionError
+	markBad := func(off int64, reason string) {
+		if corrupted == nil {
+			corrupted = &corruptionError{offset: off, reason: reason}
+		}
+		corrupted.bad++
+	}
+
+	off := int64(len(segmentMagic))
+	for off < size {
+		if size-off < recordHeaderSize {
+			break
+		}
+
+		length := int64(binary.LittleEndian.Uint32(data[off:]))
+		sum := binary.LittleEndian.Uint32(data[off+4:])
+		if length > maxRecordSize {
+			// next record position is unknown
+			markBad(off, "bad record length")
+			corrupted.unreadable = true
+			return size, off, corrupted
+		}
+		if size-off-recordHeaderSize < length {
+			break
+		}
+
+		payload := data[off+recordHeaderSize : off+recordHeaderSize+length]
+		off += recordHeaderSize + length
+		if crc32.Checksum(payload, crcTable) != sum {
+			markBad(off-recordHeaderSize-length, "checksum mismatch")
+			continue
+		}
+
+		rec, err := decodeRecord(payload)
+		if err != nil {
+			markBad(off-recordHeaderSize-length, err.Error())
+			continue
+		}
+		if fn != nil {
+			fn(rec)
+		}
+	}
+
+	if corrupted != nil {
+		return size, off, corrupted
+	}
+	return size, off, nil
+}
+
+// encodeRecord encodes record as:
+//
+//	length uint32 | crc32c uint32 | time int64 | source | fields count uint16 | (name | value float64)...
+//	[counts count uint16 | (name | count uint32)...]
+//
+// Strings are prefixed by uint16 length, all integers are little-endian.
+// Counts section is written for downsampled records only.
+func encodeRecord(rec Record) []byte {
+	fields := make([]string, 0, len(rec.Values))
+	for f := range rec.Values {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	var payload bytes.Buffer
+	_ = binary.Write(&payload, binary.LittleEndian, rec.Time.UnixNano())
+	writeString(&payload, rec.Source)
+	_ = binary.Write(&payload, binary.LittleEndian, uint16(len(fields)))
+	for _, f := range fields {
+		writeString(&payload, f)
+		_ = binary.Write(&payload, binary.LittleEndian, math.Float64bits(rec.Values[f]))
+	}
+	if rec.Counts != nil {
+		counts := make([]string, 0, len(rec.Counts))
+		for f := range rec.Counts {
+			counts = append(counts, f)
+		}
+		sort.Strings(counts)
+
+		_ = binary.Write(&payload, binary.LittleEndian, uint16(len(counts)))
+		for _, f := range counts {
+			writeString(&payload, f)
+			_ = binary.Write(&payload, binary.LittleEndian, rec.Counts[f])
+		}
+	}
+
+	out := make([]byte, recordHeaderSize, recordHeaderSize+payload.Len())
+	binary.LittleEndian.PutUint32(out, uint32(payload.Len()))
+	binary.LittleEndian.PutUint32(out[4:], crc32.Checksum(payload.Bytes(), crcTable))
+	return append(out, payload.Bytes()...)
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	_ = binary.Write(buf, binary.LittleEndian, uint16(len(s)))
+	buf.WriteString(s)
+}
+
+func decodeRecord(payload []byte) (Record, error) {
+	r := bytes.NewReader(payload)
+
+	var ts int64
+	if err := binary.Read(r, binary.LittleEndian, &ts); err != nil {
+		return Record{}, err
+	}
+	source, err := readString(r)
+	if err != nil {
+		return Record{}, err
+	}
+
+	var n uint16
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return Record{}, err
+	}
+
+	values := make(map[string]float64, n)
+	for i := 0; i < int(n); i++ {
+		name, err := readString(r)
+		if err != nil {
+			return Record{}, err
+		}
+
+		var bits uint64
+		if err := binary.Read(r, binary.LittleEndian, &bits); err != nil {
+			return Record{}, err
+		}
+		values[name] = math.Float64frombits(bits)
+	}
+	rec := Record{Time: time.Unix(0, ts), Source: source, Values: values}
+	if r.Len() == 0 {
+		return rec, nil
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return Record{}, err
+	}
+	rec.Counts = make(map[string]uint32, n)
+	for i := 0; i < int(n); i++ {
+		name, err := readString(r)
+		if err != nil {
+			return Record{}, err
+		}
+
+		var count uint32
+		if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+			return Record{}, err
+		}
+		rec.Counts[name] = count
+	}
+	return rec, nil
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,273 @@
+package history
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func openTestDisk(t *testing.T, dir string, opts DiskOptions) *DiskStore {
+	t.Helper()
+	d, err := OpenDisk(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+func TestDiskStore_AppendRead(t *testing.T) {
+	dir := tempDir(t)
+	d := openTestDisk(t, dir, DiskOptions{SegmentDuration: time.Minute})
+
+	// backfilled sample is appended after newer ones
+	for _, sec := range []int{0, 30, 60, 90, 10} {
+		if err := d.AppendSummary("10.0.0.1:4028", at(sec), cgminer.Summary{MHS5s: float64(sec)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.AppendDevs("10.0.0.1:4028", at(60), []cgminer.Devs{{GPU: 0, Temperature: 65}}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := d.ReadRange("10.0.0.1:4028", at(10), at(90))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []float64
+	for _, r := range records {
+		if r.Source == SummarySource {
+			got = append(got, r.Values[MHS5s])
+		}
+	}
+	if len(records) != 4 || len(got) != 3 || got[0] != 10 || got[1] != 30 || got[2] != 60 {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	miners, err := d.Miners()
+	if err != nil || len(miners) != 1 || miners[0] != "10.0.0.1:4028" {
+		t.Fatalf("unexpected miners: %v, %v", miners, err)
+	}
+}
+
+func TestDiskStore_TornWrite(t *testing.T) {
+	dir := tempDir(t)
+	d := openTestDisk(t, dir, DiskOptions{})
+	for i := 0; i < 3; i++ {
+		_ = d.AppendSummary("m1", at(i), cgminer.Summary{Accepted: int64(i)})
+	}
+	d.Close()
+
+	// simulate crash in the middle of record write
+	path := filepath.Join(dir, "m1", segmentName(at(0).Truncate(time.Hour), rawExt))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, data[:len(data)-5], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d = openTestDisk(t, dir, DiskOptions{})
+	records, err := d.ReadRange("m1", at(0), at(100))
+	if err != nil || len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d: %v", len(records), err)
+	}
+
+	// writer drops partial record before appending
+	if err := d.AppendSummary("m1", at(3), cgminer.Summary{Accepted: 3}); err != nil {
+		t.Fatal(err)
+	}
+	records, err = d.ReadRange("m1", at(0), at(100))
+	if err != nil || len(records) != 3 || records[2].Values[Accepted] != 3 {
+		t.Fatalf("unexpected records after append: %+v, %v", records, err)
+	}
+}
+
+func TestDiskStore_Corrupted(t *testing.T) {
+	dir := tempDir(t)
+	d := openTestDisk(t, dir, DiskOptions{})
+	for i := 0; i < 3; i++ {
+		_ = d.AppendSummary("m1", at(i), cgminer.Summary{})
+	}
+
+	path := filepath.Join(dir, "m1", segmentName(at(0).Truncate(time.Hour), rawExt))
+	data, _ := ioutil.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	_ = ioutil.WriteFile(path, data, 0644)
+
+	records, err := d.ReadRange("m1", at(0), at(100))
+	if !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("expected ErrCorrupted, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected records before corruption, got %d", len(records))
+	}
+}
+
+func TestDiskStore_CorruptedMiddle(t *testing.T) {
+	dir := tempDir(t)
+	d := openTestDisk(t, dir, DiskOptions{})
+	for i := 0; i < 5; i++ {
+		_ = d.AppendSummary("m1", at(i), cgminer.Summary{Accepted: int64(i)})
+	}
+	d.Close()
+
+	// damage payload of the second record
+	path := filepath.Join(dir, "m1", segmentName(at(0).Truncate(time.Hour), rawExt))
+	data, _ := ioutil.ReadFile(path)
+	off := len(segmentMagic)
+	off += recordHeaderSize + int(binary.LittleEndian.Uint32(data[off:]))
+	data[off+recordHeaderSize+2] ^= 0xff
+	_ = ioutil.WriteFile(path, data, 0644)
+
+	d = openTestDisk(t, dir, DiskOptions{})
+	if err := d.AppendSummary("m1", at(5), cgminer.Summary{Accepted: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := d.ReadRange("m1", at(0), at(100))
+	if !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("expected ErrCorrupted, got %v", err)
+	}
+	var got []float64
+	for _, r := range records {
+		got = append(got, r.Values[Accepted])
+	}
+	if len(got) != 5 || got[0] != 0 || got[1] != 2 || got[4] != 5 {
+		t.Fatalf("expected records around corruption, got %v", got)
+	}
+}
+
+func TestDiskStore_Compact(t *testing.T) {
+	dir := tempDir(t)
+	d := openTestDisk(t, dir, DiskOptions{
+		SegmentDuration: time.Minute,
+		Retention:       10 * time.Minute,
+		CompactAfter:    2 * time.Minute,
+		CompactWidth:    30 * time.Second,
+	})
+
+	// 12 minutes of samples every 10 seconds
+	for sec := 0; sec < 720; sec += 10 {
+		if err := d.AppendSummary("m1", at(sec), cgminer.Summary{MHS5s: float64(sec)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := d.Compact(at(720)); err != nil {
+		t.Fatal(err)
+	}
+
+	segments, _ := d.segments("m1")
+	if len(segments) != 10 {
+		t.Fatalf("expected 10 segments after retention, got %d", len(segments))
+	}
+	for i, seg := range segments {
+		if want := i < 8; seg.compacted != want {
+			t.Errorf("segment %s: expected compacted=%v", seg.path, want)
+		}
+	}
+
+	records, err := d.ReadRange("m1", at(120), at(180))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Values[MHS5s] != 130 || records[1].Values[MHS5s] != 160 {
+		t.Fatalf("unexpected downsampled records: %+v", records)
+	}
+
+	// raw data of recent segments is intact
+	if records, _ := d.ReadRange("m1", at(600), at(720)); len(records) != 12 {
+		t.Fatalf("expected 12 raw records, got %d", len(records))
+	}
+
+	// backfilled data is merged into compacted segment
+	_ = d.AppendSummary("m1", at(125), cgminer.Summary{MHS5s: 999})
+	if err := d.Compact(at(720)); err != nil {
+		t.Fatal(err)
+	}
+	records, _ = d.ReadRange("m1", at(120), at(150))
+	if len(records) != 1 || records[0].Values[MHS5s] != (120+130+140+999)/4.0 || records[0].Counts[MHS5s] != 4 {
+		t.Fatalf("unexpected merged records: %+v", records)
+	}
+}
+
+func TestDiskStore_CompactCorrupted(t *testing.T) {
+	dir := tempDir(t)
+	opts := DiskOptions{SegmentDuration: time.Minute, CompactAfter: time.Minute}
+	d := openTestDisk(t, dir, opts)
+	for sec := 0; sec < 180; sec += 10 {
+		_ = d.AppendSummary("m1", at(sec), cgminer.Summary{MHS5s: float64(sec)})
+	}
+	d.Close()
+
+	path := filepath.Join(dir, "m1", segmentName(at(0), rawExt))
+	data, _ := ioutil.ReadFile(path)
+	data[len(data)-1] ^= 0xff
+	_ = ioutil.WriteFile(path, data, 0644)
+
+	d = openTestDisk(t, dir, opts)
+	if err := d.Compact(at(180)); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("expected ErrCorrupted, got %v", err)
+	}
+
+	// corrupted segment is kept, others are compacted
+	segments, _ := d.segments("m1")
+	if len(segments) != 3 || segments[0].compacted || !segments[1].compacted {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+	if got, _ := ioutil.ReadFile(path); len(got) != len(data) {
+		t.Fatalf("corrupted segment was rewritten")
+	}
+}
+
+func TestDiskStore_InvalidMiner(t *testing.T) {
+	parent := tempDir(t)
+	dir := filepath.Join(parent, "store")
+	d := openTestDisk(t, dir, DiskOptions{Retention: time.Minute})
+
+	for _, miner := range []string{"", ".", ".."} {
+		if err := d.AppendSummary(miner, at(0), cgminer.Summary{}); !errors.Is(err, ErrInvalidMiner) {
+			t.Errorf("%q: expected ErrInvalidMiner on append, got %v", miner, err)
+		}
+		if _, err := d.ReadRange(miner, at(0), at(60)); !errors.Is(err, ErrInvalidMiner) {
+			t.Errorf("%q: expected ErrInvalidMiner on read, got %v", miner, err)
+		}
+	}
+
+	// directory which doesn't match escaped miner name is not treated as miner
+	if err := os.Mkdir(filepath.Join(dir, "%2E%2E"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if miners, err := d.Miners(); err != nil || len(miners) != 0 {
+		t.Fatalf("unexpected miners: %v, %v", miners, err)
+	}
+	if err := d.Compact(at(3600)); err != nil {
+		t.Fatal(err)
+	}
+
+	if entries, _ := ioutil.ReadDir(parent); len(entries) != 1 {
+		t.Fatalf("store wrote outside of its directory: %d entries", len(entries))
+	}
+	if entries, _ := ioutil.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("unexpected store entries: %d", len(entries))
+	}
+}
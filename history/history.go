@@ -0,0 +1,340 @@
+// Package history stores recent miner poll samples in memory.
+//
+// Samples are kept per miner and source (summary, device or pool) in
+// fixed-size columnar ring buffers and can be queried by time range,
+// downsampled into buckets or summarized with percentiles.
+//
+// DiskStore persists samples to append-only segment files, so history
+// survives restarts and can be backfilled after connectivity loss.
+package history
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// ErrOutOfOrder is returned when sample is older than the last stored sample
+var ErrOutOfOrder = errors.New("sample is older than the last stored sample")
+
+// SummarySource is source name of Summary samples
+const SummarySource = "summary"
+
+// DeviceSource returns source name of device samples
+func DeviceSource(gpu int64) string {
+	return "gpu/" + strconv.FormatInt(gpu, 10)
+}
+
+// PoolSource returns source name of pool samples
+func PoolSource(pool int64) string {
+	return "pool/" + strconv.FormatInt(pool, 10)
+}
+
+// Field names
+const (
+	MHSav              = "mhs_av"
+	MHS5s              = "mhs_5s"
+	Accepted           = "accepted"
+	Rejected           = "rejected"
+	Stale              = "stale"
+	HardwareErrors     = "hardware_errors"
+	DifficultyAccepted = "difficulty_accepted"
+	DifficultyRejected = "difficulty_rejected"
+	Elapsed            = "elapsed"
+	Temperature        = "temperature"
+	FanPercent         = "fan_percent"
+	GPUClock           = "gpu_clock"
+	MemoryClock        = "memory_clock"
+	Power              = "power"
+)
+
+// SummaryFields is list of fields stored for Summary samples
+var SummaryFields = []string{MHSav, MHS5s, Accepted, Rejected, Stale, HardwareErrors, DifficultyAccepted, DifficultyRejected, Elapsed}
+
+// DeviceFields is list of fields stored for device samples
+var DeviceFields = []string{MHSav, MHS5s, Accepted, Rejected, HardwareErrors, DifficultyAccepted, Temperature, FanPercent, GPUClock, MemoryClock, Power, Elapsed}
+
+// PoolFields is list of fields stored for pool samples
+var PoolFields = []string{Accepted, Rejected, Stale, DifficultyAccepted, DifficultyRejected}
+
+// summaryRow returns Summary values in SummaryFields order
+func summaryRow(sum cgminer.Summary) []float64 {
+	return []float64{
+		sum.MHSav, sum.MHS5s, float64(sum.Accepted), float64(sum.Rejected), float64(sum.Stale),
+		float64(sum.HardwareErrors), sum.DifficultyAccepted, sum.DifficultyRejected, float64(sum.Elapsed),
+	}
+}
+
+// deviceRow returns device values in DeviceFields order
+func deviceRow(d cgminer.Devs) []float64 {
+	return []float64{
+		d.MHSav, d.MHS5s, float64(d.Accepted), float64(d.Rejected), float64(d.HardwareErrors),
+		d.DifficultyAccepted, d.Temperature, float64(d.FanPercent), float64(d.GPUClock),
+		float64(d.MemoryClock), d.PowerConsumption, float64(d.DeviceElapsed),
+	}
+}
+
+// poolRow returns pool values in PoolFields order
+func poolRow(p cgminer.Pool) []float64 {
+	return []float64{
+		float64(p.Accepted), float64(p.Rejected), float64(p.Stale), p.DifficultyAccepted, p.DifficultyRejected,
+	}
+}
+
+// Key identifies stored series
+type Key struct {
+	Miner  string
+	Source string
+}
+
+// Point is single field value
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Bucket is aggregated field values within time bucket
+type Bucket struct {
+	Start time.Time
+	Min   float64
+	Max   float64
+	Avg   float64
+	Count int
+}
+
+// Store keeps recent samples per miner.
+//
+// Store is safe for concurrent use.
+type Store struct {
+	retention time.Duration
+	capacity  int
+
+	mu     sync.RWMutex
+	series map[Key]*ring
+}
+
+// New returns store which keeps samples for retention period.
+//
+// Interval is expected poll interval, used to size ring buffers.
+func New(retention, interval time.Duration) *Store {
+	capacity := 1
+	if interval > 0 {
+		capacity = int(retention/interval) + 1
+	}
+	return &Store{
+		retention: retention,
+		capacity:  capacity,
+		series:    make(map[Key]*ring),
+	}
+}
+
+// Add stores sample of arbitrary source.
+//
+// Fields define columns of series and are taken from the first sample,
+// fields not listed there are ignored later, missing values are stored as NaN.
+func (s *Store) Add(key Key, t time.Time, values map[string]float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.series[key]
+	if !ok {
+		fields := make([]string, 0, len(values))
+		for f := range values {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		r = newRing(s.capacity, fields)
+		s.series[key] = r
+	}
+
+	row := make([]float64, len(r.fields))
+	for i, f := range r.fields {
+		v, ok := values[f]
+		if !ok {
+			v = math.NaN()
+		}
+		row[i] = v
+	}
+	return s.push(r, t, row)
+}
+
+// AddSummary stores Summary sample
+func (s *Store) AddSummary(miner string, t time.Time, sum cgminer.Summary) error {
+	return s.addRow(Key{Miner: miner, Source: SummarySource}, SummaryFields, t, summaryRow(sum))
+}
+
+// AddDevs stores samples of each device
+func (s *Store) AddDevs(miner string, t time.Time, devs []cgminer.Devs) error {
+	for _, d := range devs {
+		if err := s.addRow(Key{Miner: miner, Source: DeviceSource(d.GPU)}, DeviceFields, t, deviceRow(d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddPools stores samples of each pool
+func (s *Store) AddPools(miner string, t time.Time, pools []cgminer.Pool) error {
+	for _, p := range pools {
+		if err := s.addRow(Key{Miner: miner, Source: PoolSource(p.Pool)}, PoolFields, t, poolRow(p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) addRow(key Key, fields []string, t time.Time, row []float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.series[key]
+	if !ok {
+		r = newRing(s.capacity, fields)
+		s.series[key] = r
+	}
+	return s.push(r, t, row)
+}
+
+func (s *Store) push(r *ring, t time.Time, row []float64) error {
+	ts := t.UnixNano()
+	if last, ok := r.last(); ok && ts < last {
+		return ErrOutOfOrder
+	}
+
+	r.push(ts, row)
+	r.expire(t.Add(-s.retention).UnixNano())
+	return nil
+}
+
+// Keys returns list of stored series
+func (s *Store) Keys() []Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]Key, 0, len(s.series))
+	for k := range s.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Miner != keys[j].Miner {
+			return keys[i].Miner < keys[j].Miner
+		}
+		return keys[i].Source < keys[j].Source
+	})
+	return keys
+}
+
+// Fields returns list of fields stored for series
+func (s *Store) Fields(key Key) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.series[key]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), r.fields...)
+}
+
+// Remove removes all series of miner
+func (s *Store) Remove(miner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k := range s.series {
+		if k.Miner == miner {
+			delete(s.series, k)
+		}
+	}
+}
+
+// Range returns field values within [from, to) in time order.
+// Zero from or to selects range from the first or up to the last sample.
+func (s *Store) Range(key Key, field string, from, to time.Time) []Point {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.series[key]
+	if !ok {
+		return nil
+	}
+
+	var points []Point
+	r.scan(field, from, to, func(t int64, v float64) {
+		points = append(points, Point{Time: time.Unix(0, t), Value: v})
+	})
+	return points
+}
+
+// Downsample aggregates field values within [from, to) into buckets
+// of passed width, aligned to from. Empty buckets are omitted.
+//
+// Zero from or to selects range from the first or up to the last sample.
+func (s *Store) Downsample(key Key, field string, from, to time.Time, width time.Duration) []Bucket {
+	if width <= 0 {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r, ok := s.series[key]
+	if !ok {
+		return nil
+	}
+
+	var (
+		buckets []Bucket
+		sum     float64
+	)
+	flush := func() {
+		if n := len(buckets); n > 0 {
+			buckets[n-1].Avg = sum / float64(buckets[n-1].Count)
+		}
+	}
+
+	origin, _ := r.bounds(from, to)
+	r.scan(field, from, to, func(t int64, v float64) {
+		start := time.Unix(0, origin+(t-origin)/int64(width)*int64(width))
+		n := len(buckets)
+		if n == 0 || !buckets[n-1].Start.Equal(start) {
+			flush()
+			buckets = append(buckets, Bucket{Start: start, Min: v, Max: v})
+			sum = 0
+			n++
+		}
+
+		b := &buckets[n-1]
+		b.Min = math.Min(b.Min, v)
+		b.Max = math.Max(b.Max, v)
+		b.Count++
+		sum += v
+	})
+	flush()
+	return buckets
+}
+
+// Percentile returns p-th percentile (0-100) of field values within [from, to),
+// using linear interpolation between closest ranks.
+func (s *Store) Percentile(key Key, field string, from, to time.Time, p float64) (float64, bool) {
+	points := s.Range(key, field, from, to)
+	if len(points) == 0 || p < 0 || p > 100 {
+		return 0, false
+	}
+
+	values := make([]float64, len(points))
+	for i, pt := range points {
+		values[i] = pt.Value
+	}
+	sort.Float64s(values)
+
+	rank := p / 100 * float64(len(values)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return values[lo] + (values[hi]-values[lo])*(rank-float64(lo)), true
+}
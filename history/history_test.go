@@ -0,0 +1,159 @@
+package history
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+var origin = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(sec int) time.Time {
+	return origin.Add(time.Duration(sec) * time.Second)
+}
+
+func fill(t *testing.T, s *Store, n int) Key {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := s.AddSummary("m1", at(i*10), cgminer.Summary{MHS5s: float64(i), Elapsed: int64(i * 10)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Key{Miner: "m1", Source: SummarySource}
+}
+
+func TestStore_Range(t *testing.T) {
+	s := New(time.Hour, 10*time.Second)
+	key := fill(t, s, 10)
+
+	points := s.Range(key, MHS5s, at(20), at(50))
+	if len(points) != 3 || points[0].Value != 2 || points[2].Value != 4 || !points[0].Time.Equal(at(20)) {
+		t.Fatalf("unexpected points: %+v", points)
+	}
+
+	if points := s.Range(key, "unknown", at(0), at(100)); points != nil {
+		t.Fatalf("unknown field should return nothing: %+v", points)
+	}
+
+	if err := s.AddSummary("m1", at(5), cgminer.Summary{}); err != ErrOutOfOrder {
+		t.Fatalf("expected ErrOutOfOrder, got %v", err)
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	// 1 minute at 10s interval holds 7 samples
+	s := New(time.Minute, 10*time.Second)
+	key := fill(t, s, 20)
+
+	points := s.Range(key, MHS5s, at(0), at(1000))
+	if len(points) != 7 || points[0].Value != 13 || points[6].Value != 19 {
+		t.Fatalf("unexpected points: %+v", points)
+	}
+
+	// gap in polling expires old samples by time
+	if err := s.AddSummary("m1", at(1000), cgminer.Summary{MHS5s: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if points := s.Range(key, MHS5s, at(0), at(2000)); len(points) != 1 {
+		t.Fatalf("expected only new sample, got %+v", points)
+	}
+}
+
+func TestStore_Downsample(t *testing.T) {
+	s := New(time.Hour, 10*time.Second)
+	key := fill(t, s, 10)
+
+	buckets := s.Downsample(key, MHS5s, at(0), at(100), 30*time.Second)
+	expected := []Bucket{
+		{Start: at(0), Min: 0, Max: 2, Avg: 1, Count: 3},
+		{Start: at(30), Min: 3, Max: 5, Avg: 4, Count: 3},
+		{Start: at(60), Min: 6, Max: 8, Avg: 7, Count: 3},
+		{Start: at(90), Min: 9, Max: 9, Avg: 9, Count: 1},
+	}
+	if len(buckets) != len(expected) {
+		t.Fatalf("unexpected buckets: %+v", buckets)
+	}
+	for i, b := range buckets {
+		if !b.Start.Equal(expected[i].Start) || b.Min != expected[i].Min || b.Max != expected[i].Max ||
+			b.Avg != expected[i].Avg || b.Count != expected[i].Count {
+			t.Errorf("%d: expected %+v, got %+v", i, expected[i], b)
+		}
+	}
+}
+
+func TestStore_DownsampleZeroRange(t *testing.T) {
+	s := New(time.Hour, 10*time.Second)
+	for i := 0; i < 10; i++ {
+		if err := s.AddSummary("m1", at(i*10+5), cgminer.Summary{MHS5s: float64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key := Key{Miner: "m1", Source: SummarySource}
+
+	if points := s.Range(key, MHS5s, time.Time{}, time.Time{}); len(points) != 10 {
+		t.Fatalf("expected all points, got %+v", points)
+	}
+
+	// buckets are aligned to the first sample
+	buckets := s.Downsample(key, MHS5s, time.Time{}, time.Time{}, 30*time.Second)
+	expected := []Bucket{
+		{Start: at(5), Min: 0, Max: 2, Avg: 1, Count: 3},
+		{Start: at(35), Min: 3, Max: 5, Avg: 4, Count: 3},
+		{Start: at(65), Min: 6, Max: 8, Avg: 7, Count: 3},
+		{Start: at(95), Min: 9, Max: 9, Avg: 9, Count: 1},
+	}
+	if len(buckets) != len(expected) {
+		t.Fatalf("unexpected buckets: %+v", buckets)
+	}
+	for i, b := range buckets {
+		if !b.Start.Equal(expected[i].Start) || b.Min != expected[i].Min || b.Max != expected[i].Max ||
+			b.Avg != expected[i].Avg || b.Count != expected[i].Count {
+			t.Errorf("%d: expected %+v, got %+v", i, expected[i], b)
+		}
+	}
+}
+
+func TestStore_Percentile(t *testing.T) {
+	s := New(time.Hour, 10*time.Second)
+	key := fill(t, s, 11)
+
+	for p, want := range map[float64]float64{0: 0, 50: 5, 95: 9.5, 100: 10} {
+		got, ok := s.Percentile(key, MHS5s, at(0), at(1000), p)
+		if !ok || math.Abs(got-want) > 1e-9 {
+			t.Errorf("p%v: expected %v, got %v", p, want, got)
+		}
+	}
+
+	if _, ok := s.Percentile(key, MHS5s, at(500), at(1000), 50); ok {
+		t.Error("empty range should have no percentile")
+	}
+}
+
+func TestStore_DevsAndPools(t *testing.T) {
+	s := New(time.Hour, time.Minute)
+	_ = s.AddDevs("m1", at(0), []cgminer.Devs{{GPU: 0, Temperature: 60}, {GPU: 1, Temperature: 70}})
+	_ = s.AddPools("m1", at(0), []cgminer.Pool{{Pool: 0, Accepted: 5}})
+	_ = s.Add(Key{Miner: "m2", Source: "custom"}, at(0), map[string]float64{"a": 1})
+	_ = s.Add(Key{Miner: "m2", Source: "custom"}, at(1), map[string]float64{"b": 1})
+
+	keys := s.Keys()
+	if len(keys) != 4 || keys[0] != (Key{"m1", "gpu/0"}) || keys[2] != (Key{"m1", "pool/0"}) {
+		t.Fatalf("unexpected keys: %+v", keys)
+	}
+
+	if points := s.Range(Key{"m1", DeviceSource(1)}, Temperature, at(0), at(1)); len(points) != 1 || points[0].Value != 70 {
+		t.Fatalf("unexpected temperature: %+v", points)
+	}
+
+	// missing values are skipped
+	if points := s.Range(Key{"m2", "custom"}, "a", at(0), at(2)); len(points) != 1 {
+		t.Fatalf("unexpected custom points: %+v", points)
+	}
+
+	s.Remove("m1")
+	if keys := s.Keys(); len(keys) != 1 {
+		t.Fatalf("unexpected keys after remove: %+v", keys)
+	}
+}
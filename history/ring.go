@@ -0,0 +1,115 @@
+package history
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ring is fixed-capacity columnar buffer of samples ordered by time.
+//
+// Each field is stored in separate column, so range scans over single
+// field touch only timestamps and that field values.
+type ring struct {
+	fields []string
+	index  map[string]int
+
+	times   []int64
+	columns [][]float64
+	start   int
+	size    int
+}
+
+func newRing(capacity int, fields []string) *ring {
+	r := &ring{
+		fields:  fields,
+		index:   make(map[string]int, len(fields)),
+		times:   make([]int64, capacity),
+		columns: make([][]float64, len(fields)),
+	}
+	for i, f := range fields {
+		r.index[f] = i
+		r.columns[i] = make([]float64, capacity)
+	}
+	return r
+}
+
+// pos returns buffer position of i-th oldest sample
+func (r *ring) pos(i int) int {
+	return (r.start + i) % len(r.times)
+}
+
+func (r *ring) last() (int64, bool) {
+	if r.size == 0 {
+		return 0, false
+	}
+	return r.times[r.pos(r.size-1)], true
+}
+
+// push appends sample, overwriting the oldest one if buffer is full
+func (r *ring) push(t int64, values []float64) {
+	var p int
+	if r.size < len(r.times) {
+		p = r.pos(r.size)
+		r.size++
+	} else {
+		p = r.start
+		r.start = (r.start + 1) % len(r.times)
+	}
+
+	r.times[p] = t
+	for i := range r.columns {
+		r.columns[i][p] = values[i]
+	}
+}
+
+// expire drops samples older than t
+func (r *ring) expire(t int64) {
+	for r.size > 0 && r.times[r.start] < t {
+		r.start = (r.start + 1) % len(r.times)
+		r.size--
+	}
+}
+
+// search returns index of first sample not older than t
+func (r *ring) search(t int64) int {
+	return sort.Search(r.size, func(i int) bool { return r.times[r.pos(i)] >= t })
+}
+
+// bounds returns [from, to) range in nanoseconds. Zero from and to
+// are clamped to the first and the last sample, as UnixNano
+// of zero time overflows.
+func (r *ring) bounds(from, to time.Time) (start, end int64) {
+	if r.size == 0 {
+		return 0, 0
+	}
+
+	start, end = from.UnixNano(), to.UnixNano()
+	if from.IsZero() {
+		start = r.times[r.start]
+	}
+	if to.IsZero() {
+		end = r.times[r.pos(r.size-1)] + 1
+	}
+	return start, end
+}
+
+// scan calls fn for each non-NaN field value within [from, to)
+func (r *ring) scan(field string, from, to time.Time, fn func(t int64, v float64)) bool {
+	col, ok := r.index[field]
+	if !ok {
+		return false
+	}
+
+	start, end := r.bounds(from, to)
+	for i := r.search(start); i < r.size; i++ {
+		p := r.pos(i)
+		if r.times[p] >= end {
+			break
+		}
+		if v := r.columns[col][p]; !math.IsNaN(v) {
+			fn(r.times[p], v)
+		}
+	}
+	return true
+}
@@ -0,0 +1,404 @@
+// Package httpapi exposes miners over REST/JSON HTTP API.
+//
+// Routes:
+//
+//	GET  /miners
+//	GET  /miners/{id}/summary
+//	GET  /miners/{id}/devs
+//	GET  /miners/{id}/pools
+//	GET  /miners/{id}/stats
+//	POST /miners/{id}/pools
+//	POST /miners/{id}/switchpool
+//	POST /miners/{id}/restart
+//	GET  /openapi.json
+//
+// Requests are authorized with bearer tokens which have read and/or
+// write scope. Set CGMiner.Cache of miners to cache read responses.
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// Scope is token permission
+type Scope string
+
+const (
+	// ReadScope allows GET requests
+	ReadScope Scope = "read"
+
+	// WriteScope allows POST requests
+	WriteScope Scope = "write"
+)
+
+// Server is HTTP gateway in front of miners.
+//
+// Server is http.Handler.
+type Server struct {
+	// Tokens maps bearer token to allowed scopes.
+	// Authorization is disabled if Tokens is nil.
+	Tokens map[string][]Scope
+
+	// Timeout limits single miner call, zero means no limit
+	// besides miner own timeout.
+	Timeout time.Duration
+
+	mu     sync.RWMutex
+	miners map[string]*cgminer.CGMiner
+}
+
+// NewServer returns gateway for passed miners keyed by miner ID
+func NewServer(miners map[string]*cgminer.CGMiner) *Server {
+	m := make(map[string]*cgminer.CGMiner, len(miners))
+	for id, miner := range miners {
+		m[id] = miner
+	}
+	return &Server{miners: m}
+}
+
+// SetMiner adds or replaces miner
+func (s *Server) SetMiner(id string, miner *cgminer.CGMiner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.miners[id] = miner
+}
+
+// RemoveMiner removes miner
+func (s *Server) RemoveMiner(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.miners, id)
+}
+
+func (s *Server) miner(id string) (*cgminer.CGMiner, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.miners[id]
+	return m, ok
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "openapi.json":
+		s.handle(w, r, ReadScope, http.MethodGet, func() { writeRaw(w, http.StatusOK, []byte(openAPI)) })
+	case path == "miners":
+		s.handle(w, r, ReadScope, http.MethodGet, func() { s.listMiners(w) })
+	case len(parts) == 3 && parts[0] == "miners":
+		s.minerRoute(w, r, parts[1], parts[2])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) minerRoute(w http.ResponseWriter, r *http.Request, id, action string) {
+	miner, ok := s.miner(id)
+	if !ok {
+		if s.authorize(w, r, ReadScope) {
+			writeError(w, http.StatusNotFound, "unknown miner "+id)
+		}
+		return
+	}
+
+	switch {
+	case action == "pools" && r.Method == http.MethodPost:
+		s.handle(w, r, WriteScope, http.MethodPost, func() { s.addPool(w, r, miner) })
+	case action == "summary" || action == "devs" || action == "pools" || action == "stats":
+		s.handle(w, r, ReadScope, http.MethodGet, func() { s.read(w, r, action, miner) })
+	case action == "switchpool":
+		s.handle(w, r, WriteScope, http.MethodPost, func() { s.switchPool(w, r, miner) })
+	case action == "restart":
+		s.handle(w, r, WriteScope, http.MethodPost, func() {
+			s.write(w, r, func(ctx context.Context) error { return miner.RestartContext(ctx) })
+		})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// handle checks method and authorization before calling fn
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, scope Scope, method string, fn func()) {
+	if !s.authorize(w, r, scope) {
+		return
+	}
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	fn()
+}
+
+// lookupToken returns scopes of token. Token is compared with all
+// configured tokens in constant time, so response time doesn't tell
+// how much of token is guessed.
+func (s *Server) lookupToken(token string) ([]Scope, bool) {
+	var (
+		scopes []Scope
+		found  bool
+	)
+	for t, sc := range s.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			scopes, found = sc, true
+		}
+	}
+	return scopes, found
+}
+
+// RequireScope returns handler which passes request to h only if
+// bearer token has passed scope. Used to protect handlers mounted
+// next to Server.
+func (s *Server) RequireScope(scope Scope, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.authorize(w, r, scope) {
+			h.ServeHTTP(w, r)
+		}
+	})
+}
+
+// TokenParam is name of query parameter and cookie with access token,
+// accepted by RequireStreamScope
+const TokenParam = "access_token"
+
+// RequireStreamScope is like RequireScope, but also accepts token passed
+// as TokenParam query parameter or cookie, since browser EventSource
+// and WebSocket can't set Authorization header.
+//
+// Use it for event streams only, URLs with tokens might end up in logs.
+func (s *Server) RequireStreamScope(scope Scope, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" {
+			token = r.URL.Query().Get(TokenParam)
+		}
+		if token == "" {
+			if c, err := r.Cookie(TokenParam); err == nil {
+				token = c.Value
+			}
+		}
+		if s.checkToken(w, token, scope) {
+			h.ServeHTTP(w, r)
+		}
+	})
+}
+
+// authorize checks bearer token scope and writes error response if needed
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, scope Scope) bool {
+	return s.checkToken(w, bearerToken(r), scope)
+}
+
+// bearerToken returns token from Authorization header
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(auth[len(prefix):])
+}
+
+// checkToken checks token scope and writes error response if needed
+func (s *Server) checkToken(w http.ResponseWriter, token string, scope Scope) bool {
+	if s.Tokens == nil {
+		return true
+	}
+
+	if token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="trm-gateway"`)
+		writeError(w, http.StatusUnauthorized, "missing bearer token")
+		return false
+	}
+
+	scopes, ok := s.lookupToken(token)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="trm-gateway", error="invalid_token"`)
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return false
+	}
+
+	for _, sc := range scopes {
+		if sc == scope {
+			return true
+		}
+	}
+	w.Header().Set("WWW-Authenticate", `Bearer realm="trm-gateway", error="insufficient_scope", scope="`+string(scope)+`"`)
+	writeError(w, http.StatusForbidden, "token has no "+string(scope)+" scope")
+	return false
+}
+
+// MinerInfo is item of GET /miners response
+type MinerInfo struct {
+	ID       string `json:"id"`
+	Address  string `json:"address"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+func (s *Server) listMiners(w http.ResponseWriter) {
+	s.mu.RLock()
+	list := make([]MinerInfo, 0, len(s.miners))
+	for id, m := range s.miners {
+		list = append(list, MinerInfo{ID: id, Address: m.Address, ReadOnly: m.ReadOnly})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) context(r *http.Request) (context.Context, context.CancelFunc) {
+	if s.Timeout > 0 {
+		return context.WithTimeout(r.Context(), s.Timeout)
+	}
+	return context.WithCancel(r.Context())
+}
+
+func (s *Server) read(w http.ResponseWriter, r *http.Request, section string, miner *cgminer.CGMiner) {
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	var (
+		result interface{}
+		err    error
+	)
+	switch section {
+	case "summary":
+		result, err = miner.SummaryContext(ctx)
+	case "devs":
+		result, err = miner.DevsContext(ctx)
+	case "pools":
+		result, err = miner.PoolsContext(ctx)
+	case "stats":
+		var stats cgminer.Stats
+		if stats, err = miner.StatsContext(ctx); err == nil {
+			result = stats.Generic()
+		}
+	}
+	if err != nil {
+		writeMinerError(w, err)
+		return
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeRaw(w, http.StatusOK, body)
+}
+
+// AddPoolRequest is body of POST /miners/{id}/pools
+type AddPoolRequest struct {
+	URL      string `json:"url"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// SwitchPoolRequest is body of POST /miners/{id}/switchpool
+type SwitchPoolRequest struct {
+	Pool int64 `json:"pool"`
+}
+
+func (s *Server) addPool(w http.ResponseWriter, r *http.Request, miner *cgminer.CGMiner) {
+	var req AddPoolRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.URL == "" {
+		writeError(w, http.StatusBadRequest, "url is required")
+		return
+	}
+
+	s.write(w, r, func(ctx context.Context) error {
+		return miner.AddPoolContext(ctx, req.URL, req.User, req.Password)
+	})
+}
+
+func (s *Server) switchPool(w http.ResponseWriter, r *http.Request, miner *cgminer.CGMiner) {
+	var req SwitchPoolRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.write(w, r, func(ctx context.Context) error {
+		return miner.SwitchPoolContext(ctx, &cgminer.Pool{Pool: req.Pool})
+	})
+}
+
+// write calls write command
+func (s *Server) write(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context) error) {
+	ctx, cancel := s.context(r)
+	defer cancel()
+
+	if err := fn(ctx); err != nil {
+		writeMinerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// maxBodySize limits request body size
+const maxBodySize = 64 << 10
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// ErrorResponse is body of error responses
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeMinerError maps miner call error to response status.
+// Connection and API errors are reported as 502.
+func writeMinerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, cgminer.ErrReadOnly):
+		writeError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, cgminer.ErrRateLimited):
+		w.Header().Set("Retry-After", "1")
+		writeError(w, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusGatewayTimeout, err.Error())
+	default:
+		writeError(w, http.StatusBadGateway, err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, ErrorResponse{Error: msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body = []byte(`{"error":"cannot encode response"}`)
+	}
+	writeRaw(w, status, body)
+}
+
+func writeRaw(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
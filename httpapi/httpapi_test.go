@@ -0,0 +1,285 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// fakeMiner answers API commands through in-memory connections
+type fakeMiner struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func (f *fakeMiner) miner() *cgminer.CGMiner {
+	m := cgminer.NewCGMiner("10.0.0.1", 4028, 5*time.Second)
+	m.Dialer = cgminer.DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go f.serve(server)
+		return client, nil
+	})
+	return m
+}
+
+func (f *fakeMiner) serve(conn net.Conn) {
+	defer conn.Close()
+	var cmd cgminer.Command
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	f.commands = append(f.commands, cmd.Command+"|"+cmd.Parameter)
+	f.mu.Unlock()
+
+	status := `"STATUS":[{"STATUS":"S","Code":1}],"id":1`
+	var rsp string
+	switch cmd.Command {
+	case "summary":
+		rsp = `{` + status + `,"SUMMARY":[{"Elapsed":100,"MHS av":12.5}]}`
+	case "devs":
+		rsp = `{` + status + `,"DEVS":[{"GPU":0},{"GPU":1}]}`
+	case "pools":
+		rsp = `{` + status + `,"POOLS":[{"POOL":0,"URL":"stratum+tcp://a:3333"}]}`
+	case "stats":
+		rsp = `{` + status + `,"STATS":[{"Elapsed":100}]}`
+	default:
+		rsp = `{` + status + `}`
+	}
+	_, _ = conn.Write(append([]byte(rsp), 0x00))
+}
+
+func (f *fakeMiner) calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+func newTestServer() (*Server, *fakeMiner) {
+	fake := &fakeMiner{}
+	m := fake.miner()
+	m.Cache = cgminer.NewResponseCache(time.Minute)
+	s := NewServer(map[string]*cgminer.CGMiner{"rig1": m})
+	s.Tokens = map[string][]Scope{
+		"reader": {ReadScope},
+		"admin":  {ReadScope, WriteScope},
+	}
+	return s, fake
+}
+
+func do(s *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Read(t *testing.T) {
+	s, fake := newTestServer()
+
+	rec := do(s, http.MethodGet, "/miners", "reader", "")
+	if rec.Code != http.StatusOK || rec.Body.String() != `[{"id":"rig1","address":"10.0.0.1:4028","read_only":false}]` {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body)
+	}
+
+	for _, section := range []string{"summary", "devs", "pools", "stats"} {
+		rec = do(s, http.MethodGet, "/miners/rig1/"+section, "reader", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: unexpected response: %d %s", section, rec.Code, rec.Body)
+		}
+	}
+
+	var sum cgminer.Summary
+	rec = do(s, http.MethodGet, "/miners/rig1/summary", "reader", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &sum); err != nil || sum.MHSav != 12.5 {
+		t.Fatalf("unexpected summary: %s", rec.Body)
+	}
+	// second request is served from miner cache
+	if calls := fake.calls(); len(calls) != 4 {
+		t.Fatalf("unexpected miner calls: %v", calls)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	s, fake := newTestServer()
+
+	cases := []struct {
+		method, path, token string
+		status              int
+	}{
+		{http.MethodGet, "/miners", "", http.StatusUnauthorized},
+		{http.MethodGet, "/miners", "wrong", http.StatusUnauthorized},
+		{http.MethodGet, "/miners/unknown/summary", "", http.StatusUnauthorized},
+		{http.MethodPost, "/miners/rig1/restart", "reader", http.StatusForbidden},
+		{http.MethodGet, "/miners/rig1/restart", "admin", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/miners/unknown/summary", "reader", http.StatusNotFound},
+		{http.MethodGet, "/miners/rig1/unknown", "reader", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		if rec := do(s, c.method, c.path, c.token, ""); rec.Code != c.status {
+			t.Errorf("%s %s (%s): expected %d, got %d %s", c.method, c.path, c.token, c.status, rec.Code, rec.Body)
+		}
+	}
+	if calls := fake.calls(); len(calls) > 0 {
+		t.Fatalf("miner should not be called: %v", calls)
+	}
+}
+
+func TestServer_Write(t *testing.T) {
+	s, fake := newTestServer()
+
+	do(s, http.MethodGet, "/miners/rig1/pools", "admin", "")
+	steps := []struct {
+		path, body, command string
+	}{
+		{"/miners/rig1/pools", `{"url":"stratum+tcp://b:3333","user":"u","password":"p"}`, "addpool|stratum+tcp://b:3333,u,p"},
+		{"/miners/rig1/switchpool", `{"pool":1}`, "switchpool|1"},
+		{"/miners/rig1/restart", ``, "restart|"},
+	}
+	for _, step := range steps {
+		rec := do(s, http.MethodPost, step.path, "admin", step.body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: unexpected response: %d %s", step.path, rec.Code, rec.Body)
+		}
+		calls := fake.calls()
+		if last := calls[len(calls)-1]; last != step.command {
+			t.Fatalf("%s: expected %q, got %q", step.path, step.command, last)
+		}
+	}
+
+	// write invalidates cache
+	do(s, http.MethodGet, "/miners/rig1/pools", "admin", "")
+	if calls := fake.calls(); calls[len(calls)-1] != "pools|" {
+		t.Fatalf("cache should be invalidated after write: %v", calls)
+	}
+
+	if rec := do(s, http.MethodPost, "/miners/rig1/pools", "admin", `{"user":"u"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for missing url, got %d", rec.Code)
+	}
+	if rec := do(s, http.MethodPost, "/miners/rig1/switchpool", "admin", `{"pool":"x"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for bad body, got %d", rec.Code)
+	}
+}
+
+func TestServer_ReadOnlyMiner(t *testing.T) {
+	s, fake := newTestServer()
+	m := fake.miner()
+	m.ReadOnly = true
+	s.SetMiner("rig1", m)
+
+	rec := do(s, http.MethodPost, "/miners/rig1/restart", "admin", "")
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestServer_RateLimited(t *testing.T) {
+	s, fake := newTestServer()
+	s.Timeout = time.Second
+	m := fake.miner()
+	m.Limiter = cgminer.NewLimiter(cgminer.Rate{Limit: 0.001, Burst: 1}, cgminer.DefaultWriteRate)
+	s.SetMiner("rig1", m)
+
+	if rec := do(s, http.MethodGet, "/miners/rig1/summary", "reader", ""); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected response: %d %s", rec.Code, rec.Body)
+	}
+
+	rec := do(s, http.MethodGet, "/miners/rig1/devs", "reader", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 429 with Retry-After, got %d %v %s", rec.Code, rec.Header(), rec.Body)
+	}
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	s, _ := newTestServer()
+	rec := do(s, http.MethodGet, "/openapi.json", "reader", "")
+
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI == "" || len(doc.Paths) != 7 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+}
+
+func TestServer_RequireScope(t *testing.T) {
+	s, _ := newTestServer()
+	h := s.RequireScope(WriteScope, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	for token, status := range map[string]int{"": http.StatusUnauthorized, "reader": http.StatusForbidden, "admin": http.StatusTeapot} {
+		req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != status {
+			t.Errorf("%q: expected %d, got %d", token, status, rec.Code)
+		}
+	}
+}
+
+func TestServer_RequireStreamScope(t *testing.T) {
+	s, _ := newTestServer()
+	h := s.RequireStreamScope(ReadScope, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	// browser EventSource and WebSocket can't set Authorization header
+	get := func(query string, cookie *http.Cookie) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/stream"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rsp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+		return rsp.StatusCode
+	}
+
+	if code := get("?miner=rig1&access_token=reader", nil); code != http.StatusTeapot {
+		t.Errorf("query token: expected %d, got %d", http.StatusTeapot, code)
+	}
+	if code := get("", &http.Cookie{Name: TokenParam, Value: "reader"}); code != http.StatusTeapot {
+		t.Errorf("cookie token: expected %d, got %d", http.StatusTeapot, code)
+	}
+	if code := get("?access_token=unknown", nil); code != http.StatusUnauthorized {
+		t.Errorf("invalid token: expected %d, got %d", http.StatusUnauthorized, code)
+	}
+	if code := get("", nil); code != http.StatusUnauthorized {
+		t.Errorf("no token: expected %d, got %d", http.StatusUnauthorized, code)
+	}
+
+	// query token is not accepted by other routes
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/miners?access_token=reader", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d for query token on API route, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
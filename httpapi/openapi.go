@@ -0,0 +1,149 @@
+package httpapi
+
+// openAPI is OpenAPI document served at /openapi.json
+const openAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "trm-gateway",
+    "description": "REST/JSON gateway in front of cgminer-compatible miner APIs",
+    "version": "1.0.0"
+  },
+  "security": [{"bearer": ["read"]}],
+  "paths": {
+    "/miners": {
+      "get": {
+        "summary": "List miners",
+        "responses": {
+          "200": {"description": "Miners", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/MinerInfo"}}}}},
+          "401": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/miners/{id}/summary": {
+      "get": {
+        "summary": "Miner summary",
+        "parameters": [{"$ref": "#/components/parameters/MinerID"}],
+        "responses": {
+          "200": {"description": "Summary", "content": {"application/json": {"schema": {"type": "object"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"},
+          "504": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/miners/{id}/devs": {
+      "get": {
+        "summary": "Miner devices",
+        "parameters": [{"$ref": "#/components/parameters/MinerID"}],
+        "responses": {
+          "200": {"description": "Devices", "content": {"application/json": {"schema": {"type": "array", "items": {"type": "object"}}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"},
+          "504": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/miners/{id}/pools": {
+      "get": {
+        "summary": "Miner pools",
+        "parameters": [{"$ref": "#/components/parameters/MinerID"}],
+        "responses": {
+          "200": {"description": "Pools", "content": {"application/json": {"schema": {"type": "array", "items": {"type": "object"}}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"},
+          "504": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "summary": "Add pool",
+        "security": [{"bearer": ["write"]}],
+        "parameters": [{"$ref": "#/components/parameters/MinerID"}],
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/AddPoolRequest"}}}},
+        "responses": {
+          "200": {"$ref": "#/components/responses/OK"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "403": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/miners/{id}/stats": {
+      "get": {
+        "summary": "Miner stats",
+        "parameters": [{"$ref": "#/components/parameters/MinerID"}],
+        "responses": {
+          "200": {"description": "Generic stats", "content": {"application/json": {"schema": {"type": "object"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"},
+          "504": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/miners/{id}/switchpool": {
+      "post": {
+        "summary": "Switch active pool",
+        "security": [{"bearer": ["write"]}],
+        "parameters": [{"$ref": "#/components/parameters/MinerID"}],
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SwitchPoolRequest"}}}},
+        "responses": {
+          "200": {"$ref": "#/components/responses/OK"},
+          "400": {"$ref": "#/components/responses/Error"},
+          "403": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/miners/{id}/restart": {
+      "post": {
+        "summary": "Restart miner",
+        "security": [{"bearer": ["write"]}],
+        "parameters": [{"$ref": "#/components/parameters/MinerID"}],
+        "responses": {
+          "200": {"$ref": "#/components/responses/OK"},
+          "403": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"},
+          "502": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer", "description": "Token with read and/or write scope"}
+    },
+    "parameters": {
+      "MinerID": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}
+    },
+    "responses": {
+      "OK": {"description": "Command accepted", "content": {"application/json": {"schema": {"type": "object", "properties": {"status": {"type": "string"}}}}}},
+      "Error": {"description": "Error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "MinerInfo": {
+        "type": "object",
+        "properties": {"id": {"type": "string"}, "address": {"type": "string"}, "read_only": {"type": "boolean"}}
+      },
+      "AddPoolRequest": {
+        "type": "object",
+        "required": ["url"],
+        "properties": {"url": {"type": "string"}, "user": {"type": "string"}, "password": {"type": "string"}}
+      },
+      "SwitchPoolRequest": {
+        "type": "object",
+        "required": ["pool"],
+        "properties": {"pool": {"type": "integer"}}
+      },
+      "Error": {
+        "type": "object",
+        "properties": {"error": {"type": "string"}}
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,163 @@
+package cgminer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when command can't be sent before context deadline
+// because miner request budget is exhausted
+var ErrRateLimited = errors.New("cgminer: rate limit exceeded")
+
+// Rate is token bucket budget
+type Rate struct {
+	// Limit is number of requests per second, zero disables limit
+	Limit float64
+
+	// Burst is number of requests which can be sent at once, at least 1
+	Burst int
+}
+
+// DefaultReadRate is read commands budget which is safe for TeamRedMiner
+// and older bmminer builds
+var DefaultReadRate = Rate{Limit: 2, Burst: 5}
+
+// DefaultWriteRate is write commands budget which is safe for TeamRedMiner
+// and older bmminer builds
+var DefaultWriteRate = Rate{Limit: 1, Burst: 3}
+
+// Limiter limits request rate per miner address, with separate
+// budgets for read and write commands.
+//
+// Single limiter should be shared by all CGMiner clients of the process,
+// so many pollers of the same rig share its budget. Set CGMiner.Limiter
+// to enable limiting.
+// Budgets of idle miners are dropped once refilled.
+//
+// When budget is exhausted, command waits for token. If token wouldn't be
+// available before context deadline, ErrRateLimited is returned at once,
+// so callers which prefer to fail fast should pass context with deadline.
+type Limiter struct {
+	// Read is budget of read commands
+	Read Rate
+
+	// Write is budget of write commands, see IsWriteCommand
+	Write Rate
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	miner string
+	write bool
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns limiter with passed read and write budgets
+func NewLimiter(read, write Rate) *Limiter {
+	return &Limiter{Read: read, Write: write}
+}
+
+// Wait takes token of miner budget for passed command, waiting for it if needed
+func (l *Limiter) Wait(ctx context.Context, miner *CGMiner, cmd Command) error {
+	return l.wait(ctx, miner.endpointID(), IsWriteCommand(cmd.Command))
+}
+
+func (l *Limiter) rate(write bool) (limit, burst float64) {
+	rate := l.Read
+	if write {
+		rate = l.Write
+	}
+	burst = float64(rate.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.Limit, burst
+}
+
+func (l *Limiter) wait(ctx context.Context, id string, write bool) error {
+	limit, burst := l.rate(write)
+	if limit <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.sweep(now)
+
+	key := bucketKey{miner: id, write: write}
+	b, ok := l.buckets[key]
+	if !ok {
+		if l.buckets == nil {
+			l.buckets = make(map[bucketKey]*bucket)
+		}
+		b = &bucket{tokens: burst}
+		l.buckets[key] = b
+	}
+
+	if !b.last.IsZero() {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit)
+	}
+	b.last = now
+
+	var delay time.Duration
+	if b.tokens < 1 {
+		delay = time.Duration((1 - b.tokens) / limit * float64(time.Second))
+		if err := ctx.Err(); err != nil {
+			l.mu.Unlock()
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+			l.mu.Unlock()
+			return ErrRateLimited
+		}
+	}
+
+	// token is reserved, later callers wait behind us
+	b.tokens--
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		b.tokens = math.Min(burst, b.tokens+1)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// sweep removes buckets which are full and idle for longer than Burst/Limit,
+// so they don't pile up for miners which are gone. Runs at most once
+// per second. Caller must hold lock.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Second {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		limit, burst := l.rate(key.write)
+		idle := now.Sub(b.last).Seconds()
+		// bucket with reserved tokens of pending callers is not full yet
+		if limit <= 0 || (idle > burst/limit && b.tokens+idle*limit >= burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
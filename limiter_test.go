@@ -0,0 +1,149 @@
+package cgminer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	fake := &fakeCached{}
+	limiter := NewLimiter(Rate{Limit: 10, Burst: 2}, Rate{Limit: 10, Burst: 1})
+	miner := fake.miner(ip, nil)
+	miner.Limiter = limiter
+
+	// second client of the same miner shares budget
+	same := fake.miner(ip, nil)
+	same.Limiter = limiter
+
+	if _, err := miner.Summary(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := same.Summary(); err != nil {
+		t.Fatal(err)
+	}
+
+	// next token is available in 100ms
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := same.SummaryContext(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("want ErrRateLimited, got %v", err)
+	}
+	if d := time.Since(start); d >= 20*time.Millisecond {
+		t.Errorf("rate limited call should fail fast, took %s", d)
+	}
+	if n := fake.count("summary"); n != 2 {
+		t.Errorf("want 2 summary requests, got %d", n)
+	}
+
+	// write budget is separate
+	if err := miner.Restart(); err != nil {
+		t.Fatal(err)
+	}
+
+	// other miner has own budget
+	other := fake.miner("10.0.0.2", nil)
+	other.Limiter = limiter
+	if _, err := other.Summary(); err != nil {
+		t.Fatal(err)
+	}
+
+	// call without deadline waits for token
+	start = time.Now()
+	if _, err := miner.Summary(); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("call should wait for token, took %s", d)
+	}
+}
+
+func TestLimiter_Cancel(t *testing.T) {
+	limiter := NewLimiter(Rate{Limit: 1, Burst: 1}, Rate{})
+	ctx := context.Background()
+
+	if err := limiter.wait(ctx, "tcp://a", false); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- limiter.wait(ctx, "tcp://a", false) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+
+	// canceled waiter returns reserved token
+	limiter.mu.Lock()
+	tokens := limiter.buckets[bucketKey{miner: "tcp://a"}].tokens
+	limiter.mu.Unlock()
+	if tokens < 0 {
+		t.Errorf("reserved token was not returned, tokens: %v", tokens)
+	}
+
+	// zero limit disables write limiting
+	for i := 0; i < 10; i++ {
+		if err := limiter.wait(context.Background(), "tcp://a", true); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLimiter_CacheHits(t *testing.T) {
+	fake := &fakeCached{}
+	miner := fake.miner(ip, NewResponseCache(time.Minute))
+	miner.Limiter = NewLimiter(Rate{Limit: 0.1, Burst: 1}, DefaultWriteRate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		if _, err := miner.SummaryContext(ctx); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if _, err := miner.PoolsContext(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("want ErrRateLimited for not cached command, got %v", err)
+	}
+}
+
+func TestLimiter_Evict(t *testing.T) {
+	limiter := NewLimiter(Rate{Limit: 100, Burst: 2}, Rate{})
+	ctx := context.Background()
+
+	for _, miner := range []string{"tcp://a", "tcp://b"} {
+		if err := limiter.wait(ctx, miner, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// bucket b refills within 20ms, bucket a is drained again
+	time.Sleep(30 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if err := limiter.wait(ctx, "tcp://a", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	limiter.mu.Lock()
+	limiter.lastSweep = time.Time{}
+	limiter.mu.Unlock()
+	if err := limiter.wait(ctx, "tcp://c", false); err != nil {
+		t.Fatal(err)
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if _, ok := limiter.buckets[bucketKey{miner: "tcp://b"}]; ok {
+		t.Error("idle bucket was not evicted")
+	}
+	if _, ok := limiter.buckets[bucketKey{miner: "tcp://a"}]; !ok {
+		t.Error("used bucket was evicted")
+	}
+	if len(limiter.buckets) != 2 {
+		t.Errorf("want 2 buckets, got %d", len(limiter.buckets))
+	}
+}
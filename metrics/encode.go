@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoder encodes points into wire format
+type Encoder interface {
+	Encode(w io.Writer, points []Point) error
+}
+
+// InfluxEncoder encodes points into InfluxDB line protocol
+type InfluxEncoder struct {
+	// Precision is timestamp precision, nanoseconds by default.
+	// Must match "precision" parameter of HTTP write endpoint.
+	Precision time.Duration
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\n`)
+	keyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\n`)
+)
+
+// Encode implements Encoder
+func (e InfluxEncoder) Encode(w io.Writer, points []Point) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range points {
+		line := e.line(p)
+		if line == "" {
+			continue
+		}
+		if _, err := bw.WriteString(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// line returns point in line protocol terminated by newline,
+// or empty string if point has no valid fields
+func (e InfluxEncoder) line(p Point) string {
+	var b strings.Builder
+	b.WriteString(measurementEscaper.Replace(p.Measurement))
+	for _, k := range sortedTags(p.Tags) {
+		if p.Tags[k] == "" {
+			// empty tag values are not allowed
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(keyEscaper.Replace(k))
+		b.WriteByte('=')
+		b.WriteString(keyEscaper.Replace(p.Tags[k]))
+	}
+
+	n := 0
+	for _, k := range sortedFields(p.Fields) {
+		v := p.Fields[k]
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+
+		if n == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(keyEscaper.Replace(k))
+		b.WriteByte('=')
+		b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		n++
+	}
+	if n == 0 {
+		return ""
+	}
+
+	if !p.Time.IsZero() {
+		precision := e.Precision
+		if precision <= 0 {
+			precision = time.Nanosecond
+		}
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(p.Time.UnixNano()/int64(precision), 10))
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// DefaultGraphiteTagOrder is order of tag values in Graphite metric path
+var DefaultGraphiteTagOrder = []string{"site", "rig", "gpu", "pool", "chain"}
+
+// GraphiteEncoder encodes points into Graphite plaintext protocol
+type GraphiteEncoder struct {
+	// Prefix is prepended to each metric path
+	Prefix string
+
+	// Tagged enables Graphite 1.1 tag format ("path;tag=value")
+	// instead of putting tag values into metric path
+	Tagged bool
+
+	// TagOrder is order of tag values in metric path, DefaultGraphiteTagOrder
+	// is used if empty. Tags not listed here are omitted from path.
+	TagOrder []string
+}
+
+var (
+	graphiteEscaper    = strings.NewReplacer(".", "_", " ", "_", ";", "_", "=", "_", "/", "_", ":", "_", "\n", "_")
+	graphiteTagEscaper = strings.NewReplacer(";", "_", " ", "_", "~", "_", "\n", "_")
+)
+
+// Encode implements Encoder
+func (e GraphiteEncoder) Encode(w io.Writer, points []Point) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range points {
+		base := e.path(p)
+		ts := strconv.FormatInt(p.Time.Unix(), 10)
+		for _, k := range sortedFields(p.Fields) {
+			v := p.Fields[k]
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+
+			line := e.metric(base, graphiteEscaper.Replace(k), p.Tags) + " " + strconv.FormatFloat(v, 'f', -1, 64) + " " + ts + "\n"
+			if _, err := bw.WriteString(line); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// path returns metric path without field name
+func (e GraphiteEncoder) path(p Point) string {
+	var parts []string
+	if e.Prefix != "" {
+		parts = append(parts, strings.TrimSuffix(e.Prefix, "."))
+	}
+
+	if !e.Tagged {
+		order := e.TagOrder
+		if len(order) == 0 {
+			order = DefaultGraphiteTagOrder
+		}
+		for _, k := range order {
+			if v, ok := p.Tags[k]; ok && v != "" {
+				if k == "gpu" || k == "pool" || k == "chain" {
+					v = k + v
+				}
+				parts = append(parts, graphiteEscaper.Replace(v))
+			}
+		}
+	}
+
+	parts = append(parts, graphiteEscaper.Replace(p.Measurement))
+	return strings.Join(parts, ".")
+}
+
+func (e GraphiteEncoder) metric(base, field string, tags map[string]string) string {
+	name := base + "." + field
+	if !e.Tagged {
+		return name
+	}
+
+	for _, k := range sortedTags(tags) {
+		if v := tags[k]; v != "" {
+			name += ";" + graphiteEscaper.Replace(k) + "=" + graphiteTagEscaper.Replace(v)
+		}
+	}
+	return name
+}
+
+func sortedTags(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFields(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,220 @@
+// Package metrics converts miner poll snapshots into InfluxDB line protocol
+// and Graphite plaintext and pushes them over UDP, TCP or HTTP.
+package metrics
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// Measurement kinds
+const (
+	SummaryKind = "summary"
+	GPUKind     = "gpu"
+	PoolKind    = "pool"
+	ChainKind   = "chain"
+)
+
+// maxChains is number of chain fields in GenericStats
+const maxChains = 16
+
+// Snapshot is result of single miner poll
+type Snapshot struct {
+	// Time is poll time
+	Time time.Time
+
+	// Tags are added to every point, e.g. "rig" and "site"
+	Tags map[string]string
+
+	Summary *cgminer.Summary
+	Devs    []cgminer.Devs
+	Pools   []cgminer.Pool
+	Stats   *cgminer.GenericStats
+}
+
+// Point is single measurement
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]float64
+	Time        time.Time
+}
+
+// Options configures conversion of snapshot into points
+type Options struct {
+	// Measurements overrides measurement name per kind.
+	// By default "cgminer_" prefix is added to kind name.
+	Measurements map[string]string
+
+	// Fields limits fields per kind. All fields are included
+	// for kinds without entry.
+	Fields map[string][]string
+}
+
+func (o Options) measurement(kind string) string {
+	if name, ok := o.Measurements[kind]; ok {
+		return name
+	}
+	return "cgminer_" + kind
+}
+
+func (o Options) filter(kind string, fields map[string]float64) map[string]float64 {
+	allowed, ok := o.Fields[kind]
+	if !ok {
+		return fields
+	}
+
+	filtered := make(map[string]float64, len(allowed))
+	for _, f := range allowed {
+		if v, ok := fields[f]; ok {
+			filtered[f] = v
+		}
+	}
+	return filtered
+}
+
+// Points converts snapshot into points
+func Points(s Snapshot, opts Options) []Point {
+	var points []Point
+	add := func(kind string, tags map[string]string, fields map[string]float64) {
+		fields = opts.filter(kind, fields)
+		if len(fields) == 0 {
+			return
+		}
+
+		all := make(map[string]string, len(s.Tags)+len(tags))
+		for k, v := range s.Tags {
+			all[k] = v
+		}
+		for k, v := range tags {
+			all[k] = v
+		}
+		points = append(points, Point{Measurement: opts.measurement(kind), Tags: all, Fields: fields, Time: s.Time})
+	}
+
+	if s.Summary != nil {
+		add(SummaryKind, nil, numericFields(*s.Summary))
+	}
+	for _, d := range s.Devs {
+		add(GPUKind, map[string]string{"gpu": strconv.FormatInt(d.GPU, 10)}, numericFields(d))
+	}
+	for _, p := range s.Pools {
+		add(PoolKind, map[string]string{"pool": strconv.FormatInt(p.Pool, 10), "url": p.URL}, numericFields(p))
+	}
+	if s.Stats != nil {
+		for _, c := range chainFields(s.Stats) {
+			add(ChainKind, map[string]string{"chain": strconv.Itoa(c.index)}, c.fields)
+		}
+	}
+	return points
+}
+
+// numericFields returns numeric and boolean fields of response struct
+// keyed by snake_case form of JSON key, e.g. "MHS av" becomes "mhs_av".
+func numericFields(v interface{}) map[string]float64 {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+
+	fields := make(map[string]float64, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		val, ok := numericValue(rv.Field(i))
+		if !ok {
+			continue
+		}
+
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			name = f.Name
+		}
+		// first field wins if several fields share JSON key
+		if key := fieldKey(name); !hasKey(fields, key) {
+			fields[key] = val
+		}
+	}
+	return fields
+}
+
+func hasKey(m map[string]float64, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func numericValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+var fieldReplacer = strings.NewReplacer(" ", "_", "%", "_percent", "-", "_", ".", "_")
+
+// fieldKey converts API key into snake_case field name
+func fieldKey(name string) string {
+	return strings.ToLower(fieldReplacer.Replace(strings.TrimSpace(name)))
+}
+
+type chain struct {
+	index  int
+	fields map[string]float64
+}
+
+// chainPrefixes maps GenericStats JSON key prefix to chain field name
+var chainPrefixes = map[string]string{
+	"chain_acn":       "asics",
+	"chain_hw":        "hardware_errors",
+	"chain_rate":      "rate",
+	"chain_rateideal": "rate_ideal",
+	"freq_avg":        "frequency_avg",
+	"temp":            "temp_pcb",
+	"temp2_":          "temp_chip",
+}
+
+// chainFields splits per-chain GenericStats fields (e.g. "chain_rate3")
+// into separate chains. Chains without ASICs are skipped.
+func chainFields(s *cgminer.GenericStats) []chain {
+	all := numericFields(*s)
+
+	chains := make(map[int]map[string]float64)
+	for prefix, name := range chainPrefixes {
+		for i := 1; i <= maxChains; i++ {
+			v, ok := all[prefix+strconv.Itoa(i)]
+			if !ok {
+				continue
+			}
+			if chains[i] == nil {
+				chains[i] = make(map[string]float64)
+			}
+			chains[i][name] = v
+		}
+	}
+
+	var result []chain
+	for i, fields := range chains {
+		if fields["asics"] > 0 {
+			result = append(result, chain{index: i, fields: fields})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].index < result[j].index })
+	return result
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+var snapshot = Snapshot{
+	Time:    time.Unix(1600000000, 0),
+	Tags:    map[string]string{"rig": "rig 1", "site": "north"},
+	Summary: &cgminer.Summary{MHSav: 120.5, Accepted: 10, HardwareErrors: 1},
+	Devs:    []cgminer.Devs{{GPU: 0, Temperature: 61, MHS5s: 30}},
+	Pools:   []cgminer.Pool{{Pool: 1, URL: "stratum+tcp://pool:3333", Accepted: 9, StratumActive: true}},
+	Stats: &cgminer.GenericStats{
+		ChainAcn1: 63, ChainRate1: 4500, Temp2_1: 70, ChainHW1: 3,
+		ChainAcn2: 0, ChainRate2: 0,
+	},
+}
+
+var onlyKeyFields = Options{
+	Fields: map[string][]string{
+		SummaryKind: {"mhs_av", "hardware_errors"},
+		GPUKind:     {"temperature"},
+		PoolKind:    {"accepted", "stratum_active"},
+		ChainKind:   {"rate", "temp_chip"},
+	},
+}
+
+func TestPoints(t *testing.T) {
+	points := Points(snapshot, Options{Measurements: map[string]string{GPUKind: "gpu"}})
+	if len(points) != 4 {
+		t.Fatalf("expected 4 points, got %+v", points)
+	}
+
+	gpu := points[1]
+	if gpu.Measurement != "gpu" || gpu.Tags["gpu"] != "0" || gpu.Tags["rig"] != "rig 1" || gpu.Fields["mhs_5s"] != 30 {
+		t.Fatalf("unexpected GPU point: %+v", gpu)
+	}
+
+	chain := points[3]
+	if chain.Measurement != "cgminer_chain" || chain.Tags["chain"] != "1" || chain.Fields["asics"] != 63 ||
+		chain.Fields["rate"] != 4500 || chain.Fields["hardware_errors"] != 3 {
+		t.Fatalf("unexpected chain point: %+v", chain)
+	}
+
+	if points[0].Fields["device_hardware_percent"] != 0 || points[0].Fields["hardware_errors"] != 1 {
+		t.Fatalf("unexpected summary fields: %+v", points[0].Fields)
+	}
+}
+
+func TestInfluxEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (InfluxEncoder{Precision: time.Second}).Encode(&buf, Points(snapshot, onlyKeyFields)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		`cgminer_summary,rig=rig\ 1,site=north hardware_errors=1,mhs_av=120.5 1600000000`,
+		`cgminer_gpu,gpu=0,rig=rig\ 1,site=north temperature=61 1600000000`,
+		`cgminer_pool,pool=1,rig=rig\ 1,site=north,url=stratum+tcp://pool:3333 accepted=9,stratum_active=1 1600000000`,
+		`cgminer_chain,chain=1,rig=rig\ 1,site=north rate=4500,temp_chip=70 1600000000`,
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestGraphiteEncoder(t *testing.T) {
+	points := Points(snapshot, onlyKeyFields)[1:2]
+
+	var buf bytes.Buffer
+	if err := (GraphiteEncoder{Prefix: "mining"}).Encode(&buf, points); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "mining.north.rig_1.gpu0.cgminer_gpu.temperature 61 1600000000\n"; buf.String() != expected {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := (GraphiteEncoder{Prefix: "mining", Tagged: true}).Encode(&buf, points); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "mining.cgminer_gpu.temperature;gpu=0;rig=rig_1;site=north 61 1600000000\n"; buf.String() != expected {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	chunks := splitLines([]byte("aaa\nbbb\ncccccccc\nd\n"), 8)
+	expected := []string{"aaa\nbbb\n", "cccccccc\n", "d\n"}
+	if len(chunks) != len(expected) {
+		t.Fatalf("unexpected chunks: %q", chunks)
+	}
+	for i, c := range chunks {
+		if string(c) != expected[i] {
+			t.Errorf("%d: expected %q, got %q", i, expected[i], c)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultUDPPayloadSize is default maximum size of UDP datagram payload
+const DefaultUDPPayloadSize = 512
+
+// Writer pushes points to metrics backend
+type Writer interface {
+	Write(ctx context.Context, points []Point) error
+	Close() error
+}
+
+// UDPWriter sends points in UDP datagrams.
+//
+// Lines are packed into datagrams not exceeding PayloadSize,
+// longer lines are sent in separate datagrams.
+type UDPWriter struct {
+	// Address is backend address, e.g. "localhost:8089"
+	Address string
+
+	Encoder Encoder
+
+	// PayloadSize is maximum datagram payload size,
+	// DefaultUDPPayloadSize is used if zero.
+	PayloadSize int
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewUDPWriter returns new UDP writer
+func NewUDPWriter(address string, encoder Encoder) *UDPWriter {
+	return &UDPWriter{Address: address, Encoder: encoder}
+}
+
+// Write implements Writer
+func (w *UDPWriter) Write(ctx context.Context, points []Point) error {
+	var buf bytes.Buffer
+	if err := w.Encoder.Encode(&buf, points); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "udp", w.Address)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	size := w.PayloadSize
+	if size <= 0 {
+		size = DefaultUDPPayloadSize
+	}
+
+	for _, packet := range splitLines(buf.Bytes(), size) {
+		if _, err := w.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Writer
+func (w *UDPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// splitLines packs newline-terminated lines into chunks of up to size bytes
+func splitLines(data []byte, size int) [][]byte {
+	var (
+		chunks [][]byte
+		start  int
+	)
+	for start < len(data) {
+		end := start
+		for end < len(data) {
+			i := bytes.IndexByte(data[end:], '\n')
+			next := len(data)
+			if i >= 0 {
+				next = end + i + 1
+			}
+			if next-start > size && end > start {
+				break
+			}
+			end = next
+		}
+		chunks = append(chunks, data[start:end])
+		start = end
+	}
+	return chunks
+}
+
+// TCPWriter sends points over persistent TCP connection,
+// reconnecting once if write fails.
+type TCPWriter struct {
+	// Address is backend address, e.g. "graphite:2003"
+	Address string
+
+	Encoder Encoder
+
+	// Timeout is dial and write timeout
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewTCPWriter returns new TCP writer
+func NewTCPWriter(address string, encoder Encoder, timeout time.Duration) *TCPWriter {
+	return &TCPWriter{Address: address, Encoder: encoder, Timeout: timeout}
+}
+
+// Write implements Writer
+func (w *TCPWriter) Write(ctx context.Context, points []Point) error {
+	var buf bytes.Buffer
+	if err := w.Encoder.Encode(&buf, points); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// connection might be closed by backend since last write
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err = w.write(ctx, buf.Bytes()); err == nil {
+			return nil
+		}
+		if w.conn != nil {
+			w.conn.Close()
+			w.conn = nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (w *TCPWriter) write(ctx context.Context, data []byte) error {
+	if w.conn == nil {
+		d := net.Dialer{Timeout: w.Timeout}
+		conn, err := d.DialContext(ctx, "tcp", w.Address)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	deadline, ok := ctx.Deadline()
+	if w.Timeout > 0 {
+		if t := time.Now().Add(w.Timeout); !ok || t.Before(deadline) {
+			deadline, ok = t, true
+		}
+	}
+	if ok {
+		if err := w.conn.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.conn.Write(data)
+	return err
+}
+
+// Close implements Writer
+func (w *TCPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// HTTPStatusError is returned by HTTPWriter when backend responds with non-2xx status
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements error
+func (err *HTTPStatusError) Error() string {
+	return fmt.Sprintf("write failed with status %d: %s", err.StatusCode, err.Body)
+}
+
+// HTTPWriter posts points to HTTP endpoint, e.g. InfluxDB "/write" or
+// "/api/v2/write" endpoint.
+type HTTPWriter struct {
+	// URL is full write URL including query parameters,
+	// e.g. "http://influx:8086/write?db=mining"
+	URL string
+
+	Encoder Encoder
+
+	// Token is sent in Authorization header if not empty
+	Token string
+
+	// Client is HTTP client, http.DefaultClient is used if nil
+	Client *http.Client
+}
+
+// NewHTTPWriter returns new HTTP writer
+func NewHTTPWriter(url string, encoder Encoder) *HTTPWriter {
+	return &HTTPWriter{URL: url, Encoder: encoder}
+}
+
+// Write implements Writer
+func (w *HTTPWriter) Write(ctx context.Context, points []Point) error {
+	var buf bytes.Buffer
+	if err := w.Encoder.Encode(&buf, points); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, &buf)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Token "+w.Token)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, 1024))
+		return &HTTPStatusError{StatusCode: rsp.StatusCode, Body: string(bytes.TrimSpace(body))}
+	}
+	_, _ = io.Copy(ioutil.Discard, rsp.Body)
+	return nil
+}
+
+// Close implements Writer
+func (w *HTTPWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testPoints = []Point{
+	{Measurement: "m", Fields: map[string]float64{"a": 1}, Time: time.Unix(1, 0)},
+	{Measurement: "m", Fields: map[string]float64{"a": 2}, Time: time.Unix(2, 0)},
+}
+
+func TestUDPWriter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	w := NewUDPWriter(conn.LocalAddr().String(), InfluxEncoder{Precision: time.Second})
+	w.PayloadSize = 8
+	defer w.Close()
+
+	if err := w.Write(context.Background(), testPoints); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for _, expected := range []string{"m a=1 1\n", "m a=2 2\n"} {
+		buf := make([]byte, 64)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:n]) != expected {
+			t.Fatalf("expected %q, got %q", expected, buf[:n])
+		}
+	}
+}
+
+func TestTCPWriter(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				s := bufio.NewScanner(conn)
+				for s.Scan() {
+					lines <- s.Text()
+				}
+			}()
+		}
+	}()
+
+	w := NewTCPWriter(ln.Addr().String(), GraphiteEncoder{}, 5*time.Second)
+	defer w.Close()
+
+	if err := w.Write(context.Background(), testPoints); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"m.a 1 1", "m.a 2 2"} {
+		select {
+		case line := <-lines:
+			if line != expected {
+				t.Fatalf("expected %q, got %q", expected, line)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for line")
+		}
+	}
+}
+
+func TestHTTPWriter(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("db") != "mining" || r.Header.Get("Authorization") != "Token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("unauthorized"))
+			return
+		}
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w := NewHTTPWriter(srv.URL+"/write?db=mining&precision=s", InfluxEncoder{Precision: time.Second})
+	w.Token = "secret"
+	if err := w.Write(context.Background(), testPoints); err != nil {
+		t.Fatal(err)
+	}
+	if body != "m a=1 1\nm a=2 2\n" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+
+	w.Token = ""
+	err := w.Write(context.Background(), testPoints)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized || statusErr.Body != "unauthorized" {
+		t.Fatalf("expected HTTPStatusError, got %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+// Package profiles applies per-GPU-model overclock profiles to miners.
+//
+// Profile maps GPU model (as reported by DevDetails) to target settings.
+// Apply maps profile to miner GPUs, applies settings in order with readback
+// verification and rolls back already applied settings on failure.
+package profiles
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// Setting is GPU setting name
+type Setting string
+
+// Supported settings
+const (
+	FanPercent  Setting = "fan"
+	Powertune   Setting = "powertune"
+	Voltage     Setting = "voltage"
+	MemoryClock Setting = "memory_clock"
+	EngineClock Setting = "engine_clock"
+	Intensity   Setting = "intensity"
+)
+
+// applyOrder is order in which settings are applied to single GPU.
+//
+// Fan goes first, so GPU is cooled before clocks are changed.
+var applyOrder = []Setting{FanPercent, Powertune, Voltage, MemoryClock, EngineClock, Intensity}
+
+// Settings is target GPU settings. Nil values are left unchanged.
+type Settings struct {
+	Intensity   *int     `json:"intensity,omitempty"`
+	EngineClock *int64   `json:"engine_clock,omitempty"`
+	MemoryClock *int64   `json:"memory_clock,omitempty"`
+	FanPercent  *int64   `json:"fan,omitempty"`
+	Voltage     *float64 `json:"voltage,omitempty"`
+	Powertune   *int64   `json:"powertune,omitempty"`
+}
+
+// values returns non-nil settings as map
+func (s Settings) values() map[Setting]float64 {
+	m := make(map[Setting]float64, len(applyOrder))
+	if s.Intensity != nil {
+		m[Intensity] = float64(*s.Intensity)
+	}
+	if s.EngineClock != nil {
+		m[EngineClock] = float64(*s.EngineClock)
+	}
+	if s.MemoryClock != nil {
+		m[MemoryClock] = float64(*s.MemoryClock)
+	}
+	if s.FanPercent != nil {
+		m[FanPercent] = float64(*s.FanPercent)
+	}
+	if s.Voltage != nil {
+		m[Voltage] = *s.Voltage
+	}
+	if s.Powertune != nil {
+		m[Powertune] = float64(*s.Powertune)
+	}
+	return m
+}
+
+// Profile describes target settings per GPU model
+type Profile struct {
+	// Name is profile name
+	Name string `json:"name"`
+
+	// Models maps GPU model name to settings.
+	//
+	// Model names are matched case-insensitively.
+	Models map[string]Settings `json:"models"`
+
+	// Default is optional settings for GPUs which model is not listed in Models
+	Default *Settings `json:"default,omitempty"`
+}
+
+// settingsFor returns settings for GPU model
+func (p Profile) settingsFor(model string) (Settings, bool) {
+	model = strings.TrimSpace(model)
+	if s, ok := p.Models[model]; ok {
+		return s, true
+	}
+
+	for name, s := range p.Models {
+		if strings.EqualFold(strings.TrimSpace(name), model) {
+			return s, true
+		}
+	}
+
+	if p.Default != nil {
+		return *p.Default, true
+	}
+	return Settings{}, false
+}
+
+// Change is difference between current and desired GPU setting
+type Change struct {
+	GPU     int64
+	Model   string
+	Setting Setting
+
+	// Current is value reported by GPU, NaN if unknown
+	Current float64
+
+	// Desired is value from profile
+	Desired float64
+}
+
+// Pending reports whether setting should be changed
+func (c Change) Pending() bool {
+	return math.IsNaN(c.Current) || math.Abs(c.Current-c.Desired) > 0.0005
+}
+
+// String implements fmt.Stringer
+func (c Change) String() string {
+	return fmt.Sprintf("GPU %d (%s) %s: %v -> %v", c.GPU, c.Model, c.Setting, c.Current, c.Desired)
+}
+
+// Diff returns current and desired values of all settings specified by profile
+// for each miner GPU.
+//
+// Use Change.Pending to check which settings differ.
+func Diff(ctx context.Context, miner *cgminer.CGMiner, profile Profile) ([]Change, error) {
+	details, err := miner.DevDetailContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device details: %w", err)
+	}
+
+	devs, err := miner.DevsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+
+	models := make(map[int64]string, len(details))
+	for _, d := range details {
+		models[int64(d.Id)] = d.Model
+	}
+
+	gpus := append([]cgminer.Devs(nil), (*devs)...)
+	sort.Slice(gpus, func(i, j int) bool { return gpus[i].GPU < gpus[j].GPU })
+
+	var changes []Change
+	for _, dev := range gpus {
+		model := models[dev.GPU]
+		settings, ok := profile.settingsFor(model)
+		if !ok {
+			continue
+		}
+
+		desired := settings.values()
+		for _, setting := range applyOrder {
+			want, ok := desired[setting]
+			if !ok {
+				continue
+			}
+			changes = append(changes, Change{
+				GPU:     dev.GPU,
+				Model:   model,
+				Setting: setting,
+				Current: currentValue(dev, setting),
+				Desired: want,
+			})
+		}
+	}
+	return changes, nil
+}
+
+func currentValue(dev cgminer.Devs, setting Setting) float64 {
+	switch setting {
+	case Intensity:
+		v, err := strconv.ParseFloat(dev.Intensity, 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return v
+	case EngineClock:
+		return float64(dev.GPUClock)
+	case MemoryClock:
+		return float64(dev.MemoryClock)
+	case FanPercent:
+		return float64(dev.FanPercent)
+	case Voltage:
+		return dev.GPUVoltage
+	case Powertune:
+		return float64(dev.Powertune)
+	default:
+		return math.NaN()
+	}
+}
+
+// RollbackTimeout limits time of reverting changes after failed Apply.
+//
+// Rollback doesn't use Apply context, so changes are reverted even if
+// the context was canceled or its deadline exceeded.
+var RollbackTimeout = 30 * time.Second
+
+// ApplyError is returned by Apply when setting failed to apply
+type ApplyError struct {
+	// Failed is change which failed to apply
+	Failed Change
+
+	// Err is failure reason
+	Err error
+
+	// RolledBack is list of changes which were reverted
+	RolledBack []Change
+
+	// RollbackErrors contains errors occurred during rollback
+	RollbackErrors []error
+}
+
+// Error implements error
+func (err *ApplyError) Error() string {
+	msg := fmt.Sprintf("failed to apply %s: %s", err.Failed, err.Err)
+	if len(err.RollbackErrors) > 0 {
+		msg += fmt.Sprintf(" (rollback failed: %d errors, first: %s)", len(err.RollbackErrors), err.RollbackErrors[0])
+	}
+	return msg
+}
+
+// Unwrap implements error
+func (err *ApplyError) Unwrap() error {
+	return err.Err
+}
+
+// Apply applies profile to miner GPUs and returns list of applied changes.
+//
+// Settings are applied GPU by GPU in fixed order (fan, powertune, voltage,
+// memory clock, engine clock, intensity), each one verified by readback.
+// On failure, already applied changes and the failed one are reverted
+// in reverse order and *ApplyError is returned.
+func Apply(ctx context.Context, miner *cgminer.CGMiner, profile Profile) ([]Change, error) {
+	changes, err := Diff(ctx, miner, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Change
+	for _, c := range changes {
+		if !c.Pending() {
+			continue
+		}
+
+		if err := set(ctx, miner, c.GPU, c.Setting, c.Desired); err != nil {
+			applyErr := &ApplyError{Failed: c, Err: err}
+
+			// command might be applied even if readback failed
+			if !math.IsNaN(c.Current) {
+				applied = append(applied, c)
+			}
+
+			rollbackCtx, cancel := context.WithTimeout(detachedContext{ctx}, RollbackTimeout)
+			rollback(rollbackCtx, miner, applied, applyErr)
+			cancel()
+			return nil, applyErr
+		}
+		applied = append(applied, c)
+	}
+	return applied, nil
+}
+
+func rollback(ctx context.Context, miner *cgminer.CGMiner, applied []Change, applyErr *ApplyError) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		c := applied[i]
+		if math.IsNaN(c.Current) {
+			applyErr.RollbackErrors = append(applyErr.RollbackErrors,
+				fmt.Errorf("cannot revert %s: previous value is unknown", c))
+			continue
+		}
+
+		if err := set(ctx, miner, c.GPU, c.Setting, c.Current); err != nil {
+			applyErr.RollbackErrors = append(applyErr.RollbackErrors, fmt.Errorf("cannot revert %s: %w", c, err))
+			continue
+		}
+		applyErr.RolledBack = append(applyErr.RolledBack, c)
+	}
+}
+
+// detachedContext keeps parent values, but not its cancellation and deadline
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+func set(ctx context.Context, miner *cgminer.CGMiner, gpu int64, setting Setting, v float64) error {
+	switch setting {
+	case Intensity:
+		return miner.SetGPUIntensityContext(ctx, gpu, int(math.Round(v)))
+	case EngineClock:
+		return miner.SetGPUEngineClockContext(ctx, gpu, int64(math.Round(v)))
+	case MemoryClock:
+		return miner.SetGPUMemoryClockContext(ctx, gpu, int64(math.Round(v)))
+	case FanPercent:
+		return miner.SetGPUFanContext(ctx, gpu, int64(math.Round(v)))
+	case Voltage:
+		return miner.SetGPUVoltageContext(ctx, gpu, v)
+	case Powertune:
+		return miner.SetGPUPowertuneContext(ctx, gpu, int64(math.Round(v)))
+	default:
+		return fmt.Errorf("unknown setting %q", setting)
+	}
+}
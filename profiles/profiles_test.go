@@ -0,0 +1,253 @@
+package profiles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// fakeMiner is stateful fake API with several GPUs
+type fakeMiner struct {
+	mu       sync.Mutex
+	models   []string
+	devs     []cgminer.Devs
+	commands []string
+
+	// ignore makes fake device to ignore setting command for GPU,
+	// e.g. "gpuengine|1"
+	ignore string
+
+	// cancel is called when setting command for GPU matches cancelOn
+	cancel   context.CancelFunc
+	cancelOn string
+}
+
+func (f *fakeMiner) miner() *cgminer.CGMiner {
+	miner := cgminer.NewCGMiner("127.0.0.1", 4028, 5*time.Second)
+	miner.Dialer = cgminer.DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		client, server := net.Pipe()
+		go f.serve(server)
+		return client, nil
+	})
+	return miner
+}
+
+func (f *fakeMiner) serve(conn net.Conn) {
+	defer conn.Close()
+	var cmd cgminer.Command
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := `[{"STATUS":"S","Code":1,"Msg":"ok"}]`
+	var rsp string
+	switch cmd.Command {
+	case "devdetails":
+		details := make([]cgminer.DeviceDetail, len(f.models))
+		for i, m := range f.models {
+			details[i] = cgminer.DeviceDetail{Id: i, Model: m}
+		}
+		b, _ := json.Marshal(details)
+		rsp = fmt.Sprintf(`{"STATUS":%s,"DEVDETAILS":%s,"id":1}`, status, b)
+	case "devs":
+		b, _ := json.Marshal(f.devs)
+		rsp = fmt.Sprintf(`{"STATUS":%s,"DEVS":%s,"id":1}`, status, b)
+	case "gpu":
+		gpu, _ := strconv.Atoi(cmd.Parameter)
+		b, _ := json.Marshal(f.devs[gpu])
+		rsp = fmt.Sprintf(`{"STATUS":%s,"GPU":[%s],"id":1}`, status, b)
+	default:
+		f.commands = append(f.commands, cmd.Command+"|"+cmd.Parameter)
+		params := strings.Split(cmd.Parameter, ",")
+		gpu, _ := strconv.Atoi(params[0])
+		v, _ := strconv.ParseFloat(params[1], 64)
+		if f.ignore != cmd.Command+"|"+params[0] {
+			dev := &f.devs[gpu]
+			switch cmd.Command {
+			case "gpuintensity":
+				dev.Intensity = params[1]
+			case "gpuengine":
+				dev.GPUClock = int64(v)
+			case "gpumem":
+				dev.MemoryClock = int64(v)
+			case "gpufan":
+				dev.FanPercent = int64(v)
+			case "gpuvddc":
+				dev.GPUVoltage = v
+			case "gpupowertune":
+				dev.Powertune = int64(v)
+			}
+		}
+		if f.cancel != nil && f.cancelOn == cmd.Command+"|"+params[0] {
+			f.cancel()
+		}
+		rsp = fmt.Sprintf(`{"STATUS":%s,"id":1}`, status)
+	}
+	_, _ = conn.Write(append([]byte(rsp), 0x00))
+}
+
+func newFakeMiner() *fakeMiner {
+	return &fakeMiner{
+		models: []string{"Radeon RX 580", "Radeon RX 5700 XT"},
+		devs: []cgminer.Devs{
+			{GPU: 0, Intensity: "20", GPUClock: 1300, MemoryClock: 2000, FanPercent: 50},
+			{GPU: 1, Intensity: "20", GPUClock: 1700, MemoryClock: 1750, FanPercent: 60},
+		},
+	}
+}
+
+func int64p(v int64) *int64 { return &v }
+
+func intp(v int) *int { return &v }
+
+var testProfile = Profile{
+	Name: "eth",
+	Models: map[string]Settings{
+		"radeon rx 580":     {EngineClock: int64p(1150), MemoryClock: int64p(2100), FanPercent: int64p(70)},
+		"Radeon RX 5700 XT": {EngineClock: int64p(1400), Intensity: intp(20)},
+	},
+}
+
+func TestDiff(t *testing.T) {
+	fake := newFakeMiner()
+	changes, err := Diff(context.Background(), fake.miner(), testProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"GPU 0 (Radeon RX 580) fan: 50 -> 70",
+		"GPU 0 (Radeon RX 580) memory_clock: 2000 -> 2100",
+		"GPU 0 (Radeon RX 580) engine_clock: 1300 -> 1150",
+		"GPU 1 (Radeon RX 5700 XT) engine_clock: 1700 -> 1400",
+		"GPU 1 (Radeon RX 5700 XT) intensity: 20 -> 20",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+	for i, c := range changes {
+		if c.String() != expected[i] {
+			t.Errorf("%d: expected %q, got %q", i, expected[i], c)
+		}
+	}
+	if changes[4].Pending() {
+		t.Error("unchanged intensity should not be pending")
+	}
+	if len(fake.commands) > 0 {
+		t.Fatalf("Diff should not change settings: %v", fake.commands)
+	}
+}
+
+func TestApply(t *testing.T) {
+	fake := newFakeMiner()
+	applied, err := Apply(context.Background(), fake.miner(), testProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != 4 {
+		t.Fatalf("expected 4 applied changes, got %v", applied)
+	}
+
+	expected := "gpufan|0,70 gpumem|0,2100 gpuengine|0,1150 gpuengine|1,1400"
+	if got := strings.Join(fake.commands, " "); got != expected {
+		t.Fatalf("unexpected commands:\nwant %s\ngot  %s", expected, got)
+	}
+
+	if fake.devs[0].GPUClock != 1150 || fake.devs[1].GPUClock != 1400 {
+		t.Fatalf("unexpected device state: %+v", fake.devs)
+	}
+}
+
+func TestApply_Rollback(t *testing.T) {
+	fake := newFakeMiner()
+	fake.ignore = "gpuengine|1"
+
+	_, err := Apply(context.Background(), fake.miner(), testProfile)
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("expected ApplyError, got %v", err)
+	}
+
+	var settingErr *cgminer.GPUSettingError
+	if !errors.As(err, &settingErr) {
+		t.Fatalf("expected GPUSettingError to be wrapped, got %v", applyErr.Err)
+	}
+	if applyErr.Failed.GPU != 1 || applyErr.Failed.Setting != EngineClock {
+		t.Fatalf("unexpected failed change: %s", applyErr.Failed)
+	}
+	if len(applyErr.RolledBack) != 4 || len(applyErr.RollbackErrors) > 0 {
+		t.Fatalf("unexpected rollback result: %+v", applyErr)
+	}
+
+	expected := "gpufan|0,70 gpumem|0,2100 gpuengine|0,1150 gpuengine|1,1400 " +
+		"gpuengine|1,1700 gpuengine|0,1300 gpumem|0,2000 gpufan|0,50"
+	if got := strings.Join(fake.commands, " "); got != expected {
+		t.Fatalf("unexpected commands:\nwant %s\ngot  %s", expected, got)
+	}
+
+	original := newFakeMiner().devs
+	for i := range original {
+		if fake.devs[i] != original[i] {
+			t.Errorf("GPU %d was not restored: %+v", i, fake.devs[i])
+		}
+	}
+}
+
+func TestApply_RollbackCanceled(t *testing.T) {
+	fake := newFakeMiner()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake.cancel = cancel
+	fake.cancelOn = "gpumem|0"
+
+	_, err := Apply(ctx, fake.miner(), testProfile)
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("expected ApplyError, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", applyErr.Err)
+	}
+	if applyErr.Failed.GPU != 0 || applyErr.Failed.Setting != MemoryClock {
+		t.Fatalf("unexpected failed change: %s", applyErr.Failed)
+	}
+	if len(applyErr.RolledBack) != 2 || len(applyErr.RollbackErrors) > 0 {
+		t.Fatalf("unexpected rollback result: %+v", applyErr)
+	}
+
+	expected := "gpufan|0,70 gpumem|0,2100 gpumem|0,2000 gpufan|0,50"
+	if got := strings.Join(fake.commands, " "); got != expected {
+		t.Fatalf("unexpected commands:\nwant %s\ngot  %s", expected, got)
+	}
+	if fake.devs[0] != newFakeMiner().devs[0] {
+		t.Errorf("GPU 0 was not restored: %+v", fake.devs[0])
+	}
+}
+
+func TestApply_Default(t *testing.T) {
+	fake := newFakeMiner()
+	profile := Profile{Default: &Settings{FanPercent: int64p(80)}}
+
+	if _, err := Apply(context.Background(), fake.miner(), profile); err != nil {
+		t.Fatal(err)
+	}
+	if fake.devs[0].FanPercent != 80 || fake.devs[1].FanPercent != 80 {
+		t.Fatalf("default settings were not applied: %+v", fake.devs)
+	}
+}
@@ -0,0 +1,139 @@
+package proxydialer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+var _ cgminer.Dialer = (*HTTPConnectDialer)(nil)
+
+// HTTPStatusError is returned when HTTP proxy responded to CONNECT with non-2xx status
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+// Error implements error
+func (err *HTTPStatusError) Error() string {
+	return fmt.Sprintf("CONNECT failed with status %q", err.Status)
+}
+
+// HTTPConnectDialer is cgminer.Dialer which connects through HTTP proxy
+// using CONNECT method.
+//
+// Basic authentication is used when Username is set.
+type HTTPConnectDialer struct {
+	// Address is proxy server address (host:port)
+	Address string
+
+	// Username and Password are optional proxy credentials
+	Username string
+	Password string
+
+	// Header is optional extra headers sent with CONNECT request
+	Header http.Header
+
+	// Timeout is proxy connection and negotiation timeout
+	Timeout time.Duration
+
+	// Forward is dialer used to connect to proxy server.
+	//
+	// net.Dialer is used if nil.
+	Forward cgminer.Dialer
+}
+
+// NewHTTPConnectDialer returns HTTP CONNECT proxy dialer
+func NewHTTPConnectDialer(address, username, password string, timeout time.Duration) *HTTPConnectDialer {
+	return &HTTPConnectDialer{
+		Address:  address,
+		Username: username,
+		Password: password,
+		Timeout:  timeout,
+	}
+}
+
+// Dial implements cgminer.Dialer
+func (d *HTTPConnectDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext implements cgminer.Dialer
+func (d *HTTPConnectDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if err := checkNetwork(network); err != nil {
+		return nil, d.wrapError(address, err)
+	}
+
+	conn, err := defaultForward(d.Forward, d.Timeout).DialContext(ctx, "tcp", d.Address)
+	if err != nil {
+		return nil, d.wrapError(address, err)
+	}
+
+	var br *bufio.Reader
+	err = handshake(ctx, conn, d.Timeout, func(conn net.Conn) error {
+		br, err = d.connect(conn, address)
+		return err
+	})
+	if err != nil {
+		return nil, d.wrapError(address, err)
+	}
+
+	if br.Buffered() > 0 {
+		// proxy already sent some data from target
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+func (d *HTTPConnectDialer) wrapError(target string, err error) error {
+	return &ProxyError{Proxy: "http", Address: d.Address, Target: target, err: err}
+}
+
+func (d *HTTPConnectDialer) connect(conn net.Conn, address string) (*bufio.Reader, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		Host:   address,
+		URL:    &url.URL{Opaque: address},
+		Header: make(http.Header),
+	}
+	for k, v := range d.Header {
+		req.Header[k] = v
+	}
+	if d.Username != "" {
+		creds := base64.StdEncoding.EncodeToString([]byte(d.Username + ":" + d.Password))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	rsp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	_ = rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return nil, &HTTPStatusError{StatusCode: rsp.StatusCode, Status: rsp.Status}
+	}
+	return br, nil
+}
+
+// bufferedConn is net.Conn which reads data buffered during CONNECT handshake first
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,89 @@
+// Package proxydialer provides cgminer.Dialer implementations which connect
+// to miner API through SOCKS5 or HTTP CONNECT proxy.
+//
+// Errors returned by proxy handshake are reported as *ProxyError
+// and are wrapped by cgminer.CGMiner into cgminer.ConnectError.
+package proxydialer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// ProxyError represents proxy handshake or negotiation error
+type ProxyError struct {
+	// Proxy is proxy type ("socks5" or "http")
+	Proxy string
+
+	// Address is proxy server address
+	Address string
+
+	// Target is requested destination address
+	Target string
+
+	err error
+}
+
+// Error implements error
+func (err *ProxyError) Error() string {
+	return fmt.Sprintf("%s proxy %s: cannot connect to %s: %s", err.Proxy, err.Address, err.Target, err.err)
+}
+
+// Unwrap implements error
+func (err *ProxyError) Unwrap() error {
+	return err.err
+}
+
+func defaultForward(forward cgminer.Dialer, timeout time.Duration) cgminer.Dialer {
+	if forward != nil {
+		return forward
+	}
+	return &net.Dialer{Timeout: timeout}
+}
+
+// handshake runs proxy negotiation over conn and aborts it when context is done.
+//
+// Connection is closed if handshake fails.
+func handshake(ctx context.Context, conn net.Conn, timeout time.Duration, fn func(conn net.Conn) error) error {
+	ctxDeadline, hasCtxDeadline := ctx.Deadline()
+	deadline, ok := ctxDeadline, hasCtxDeadline
+	if timeout > 0 && (!ok || time.Until(deadline) > timeout) {
+		deadline, ok = time.Now().Add(timeout), true
+	}
+	if ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			// unblock pending reads and writes
+			_ = conn.SetDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	err := fn(conn)
+	close(done)
+	<-stopped
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	} else if err != nil && hasCtxDeadline && !time.Now().Before(ctxDeadline) {
+		// connection deadline might fire a bit earlier than context is done
+		err = context.DeadlineExceeded
+	}
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	return nil
+}
@@ -0,0 +1,262 @@
+package proxydialer
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+const (
+	testTimeout  = 5 * time.Second
+	testUser     = "rig"
+	testPassword = "secret"
+)
+
+func listen(t *testing.T, handler func(conn net.Conn)) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go handler(conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func startMiner(t *testing.T, fixture string) string {
+	payload, err := ioutil.ReadFile(path.Join("..", "testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload = append(payload, 0x00)
+
+	return listen(t, func(conn net.Conn) {
+		defer conn.Close()
+		_, _ = bufio.NewReader(conn).ReadBytes('\n')
+		_, _ = conn.Write(payload)
+	})
+}
+
+func pipe(client net.Conn, target string) {
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		_ = client.Close()
+		return
+	}
+	go func() {
+		_, _ = io.Copy(upstream, client)
+		_ = upstream.Close()
+	}()
+	_, _ = io.Copy(client, upstream)
+	_ = client.Close()
+}
+
+// startSOCKS5 starts minimal SOCKS5 server which requires username/password auth
+func startSOCKS5(t *testing.T) string {
+	return listen(t, func(conn net.Conn) {
+		buf := make([]byte, 512)
+		if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+			_ = conn.Close()
+			return
+		}
+		if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+			_ = conn.Close()
+			return
+		}
+
+		_, _ = conn.Write([]byte{socks5Version, socks5AuthPassword})
+		_, _ = io.ReadFull(conn, buf[:2])
+		user := make([]byte, buf[1])
+		_, _ = io.ReadFull(conn, user)
+		_, _ = io.ReadFull(conn, buf[:1])
+		pass := make([]byte, buf[0])
+		_, _ = io.ReadFull(conn, pass)
+		if string(user) != testUser || string(pass) != testPassword {
+			_, _ = conn.Write([]byte{0x01, 0x01})
+			_ = conn.Close()
+			return
+		}
+		_, _ = conn.Write([]byte{0x01, 0x00})
+
+		_, _ = io.ReadFull(conn, buf[:4])
+		var host string
+		switch buf[3] {
+		case socks5AddrIPv4:
+			_, _ = io.ReadFull(conn, buf[:4])
+			host = net.IP(buf[:4]).String()
+		case socks5AddrDomain:
+			_, _ = io.ReadFull(conn, buf[:1])
+			name := make([]byte, buf[0])
+			_, _ = io.ReadFull(conn, name)
+			host = string(name)
+		}
+		_, _ = io.ReadFull(conn, buf[:2])
+		target := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2]))))
+
+		if _, err := net.DialTimeout("tcp", target, testTimeout); err != nil {
+			_, _ = conn.Write([]byte{socks5Version, 0x05, 0, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+			_ = conn.Close()
+			return
+		}
+		_, _ = conn.Write([]byte{socks5Version, 0, 0, socks5AddrIPv4, 127, 0, 0, 1, 0, 0})
+		pipe(conn, target)
+	})
+}
+
+// startHTTPProxy starts minimal HTTP CONNECT proxy which requires basic auth
+func startHTTPProxy(t *testing.T) string {
+	creds := "Basic " + base64.StdEncoding.EncodeToString([]byte(testUser+":"+testPassword))
+	return listen(t, func(conn net.Conn) {
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			_ = conn.Close()
+			return
+		}
+
+		switch {
+		case req.Method != http.MethodConnect:
+			_, _ = io.WriteString(conn, "HTTP/1.1 405 Method Not Allowed\r\n\r\n")
+		case req.Header.Get("Proxy-Authorization") != creds:
+			_, _ = io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+		default:
+			_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+			pipe(conn, req.Host)
+			return
+		}
+		_ = conn.Close()
+	})
+}
+
+// startBlackhole starts server which accepts connections but never responds
+func startBlackhole(t *testing.T) string {
+	return listen(t, func(conn net.Conn) {
+		_, _ = io.Copy(ioutil.Discard, conn)
+	})
+}
+
+func newTestMiner(addr string, dialer cgminer.Dialer) *cgminer.CGMiner {
+	return &cgminer.CGMiner{
+		Address:   addr,
+		Timeout:   testTimeout,
+		Dialer:    dialer,
+		Transport: cgminer.NewJSONTransport(),
+	}
+}
+
+func TestProxyDialers(t *testing.T) {
+	minerAddr := startMiner(t, "TestVersion.json")
+	socksAddr := startSOCKS5(t)
+	httpAddr := startHTTPProxy(t)
+
+	cases := map[string]struct {
+		dialer cgminer.Dialer
+		target string
+		check  func(t *testing.T, err error)
+	}{
+		"socks5": {
+			dialer: NewSOCKS5Dialer(socksAddr, testUser, testPassword, testTimeout),
+			target: minerAddr,
+		},
+		"socks5 bad auth": {
+			dialer: NewSOCKS5Dialer(socksAddr, testUser, "wrong", testTimeout),
+			target: minerAddr,
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrSOCKS5AuthFailed) {
+					t.Fatalf("expected ErrSOCKS5AuthFailed, got %v", err)
+				}
+			},
+		},
+		"socks5 target refused": {
+			dialer: NewSOCKS5Dialer(socksAddr, testUser, testPassword, testTimeout),
+			target: "127.0.0.1:1",
+			check: func(t *testing.T, err error) {
+				var replyErr SOCKS5ReplyError
+				if !errors.As(err, &replyErr) || replyErr != 0x05 {
+					t.Fatalf("expected connection refused reply, got %v", err)
+				}
+			},
+		},
+		"http": {
+			dialer: NewHTTPConnectDialer(httpAddr, testUser, testPassword, testTimeout),
+			target: minerAddr,
+		},
+		"http bad auth": {
+			dialer: NewHTTPConnectDialer(httpAddr, testUser, "wrong", testTimeout),
+			target: minerAddr,
+			check: func(t *testing.T, err error) {
+				var statusErr *HTTPStatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusProxyAuthRequired {
+					t.Fatalf("expected 407 status error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			version, err := newTestMiner(c.target, c.dialer).Version()
+			if c.check == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if version.Type != "Antminer S9" {
+					t.Fatalf("unexpected version response: %+v", version)
+				}
+				return
+			}
+
+			if _, ok := err.(cgminer.ConnectError); !ok {
+				t.Fatalf("expected ConnectError, got %T (%v)", err, err)
+			}
+			var proxyErr *ProxyError
+			if !errors.As(err, &proxyErr) {
+				t.Fatalf("expected ProxyError, got %v", err)
+			}
+			c.check(t, err)
+		})
+	}
+}
+
+func TestProxyDialers_ContextCancel(t *testing.T) {
+	addr := startBlackhole(t)
+	dialers := map[string]cgminer.Dialer{
+		"socks5": NewSOCKS5Dialer(addr, "", "", testTimeout),
+		"http":   NewHTTPConnectDialer(addr, "", "", testTimeout),
+	}
+
+	for name, dialer := range dialers {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := dialer.DialContext(ctx, "tcp", "127.0.0.1:4028")
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("dial was not cancelled in time (%s)", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package proxydialer
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+var _ cgminer.Dialer = (*SOCKS5Dialer)(nil)
+
+const (
+	socks5Version = 0x05
+
+	socks5AuthNone     = 0x00
+	socks5AuthPassword = 0x02
+	socks5AuthNoAccept = 0xff
+
+	socks5CmdConnect = 0x01
+
+	socks5AddrIPv4   = 0x01
+	socks5AddrDomain = 0x03
+	socks5AddrIPv6   = 0x04
+)
+
+// ErrSOCKS5AuthFailed is returned when SOCKS5 proxy rejected credentials
+var ErrSOCKS5AuthFailed = errors.New("authentication failed")
+
+// SOCKS5ReplyError is non-successful SOCKS5 CONNECT reply
+type SOCKS5ReplyError byte
+
+var socks5Replies = map[SOCKS5ReplyError]string{
+	0x01: "general SOCKS server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// Error implements error
+func (code SOCKS5ReplyError) Error() string {
+	if msg, ok := socks5Replies[code]; ok {
+		return msg
+	}
+	return fmt.Sprintf("unknown SOCKS5 reply code %d", byte(code))
+}
+
+// SOCKS5Dialer is cgminer.Dialer which connects through SOCKS5 proxy (RFC 1928).
+//
+// Username/password authentication (RFC 1929) is used when Username is set.
+type SOCKS5Dialer struct {
+	// Address is proxy server address (host:port)
+	Address string
+
+	// Username and Password are optional proxy credentials
+	Username string
+	Password string
+
+	// Timeout is proxy connection and negotiation timeout
+	Timeout time.Duration
+
+	// Forward is dialer used to connect to proxy server.
+	//
+	// net.Dialer is used if nil.
+	Forward cgminer.Dialer
+}
+
+// NewSOCKS5Dialer returns SOCKS5 proxy dialer
+func NewSOCKS5Dialer(address, username, password string, timeout time.Duration) *SOCKS5Dialer {
+	return &SOCKS5Dialer{
+		Address:  address,
+		Username: username,
+		Password: password,
+		Timeout:  timeout,
+	}
+}
+
+// Dial implements cgminer.Dialer
+func (d *SOCKS5Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext implements cgminer.Dialer
+func (d *SOCKS5Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if err := checkNetwork(network); err != nil {
+		return nil, d.wrapError(address, err)
+	}
+
+	conn, err := defaultForward(d.Forward, d.Timeout).DialContext(ctx, "tcp", d.Address)
+	if err != nil {
+		return nil, d.wrapError(address, err)
+	}
+
+	err = handshake(ctx, conn, d.Timeout, func(conn net.Conn) error {
+		return d.negotiate(conn, address)
+	})
+	if err != nil {
+		return nil, d.wrapError(address, err)
+	}
+
+	return conn, nil
+}
+
+func (d *SOCKS5Dialer) wrapError(target string, err error) error {
+	return &ProxyError{Proxy: "socks5", Address: d.Address, Target: target, err: err}
+}
+
+func (d *SOCKS5Dialer) negotiate(conn net.Conn, address string) error {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid port %q", portStr)
+	}
+
+	method := byte(socks5AuthNone)
+	if d.Username != "" {
+		method = socks5AuthPassword
+	}
+	if _, err = conn.Write([]byte{socks5Version, 1, method}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 2)
+	if _, err = io.ReadFull(conn, buf); err != nil {
+		return err
+	}
+	if buf[0] != socks5Version {
+		return fmt.Errorf("unexpected SOCKS version %d", buf[0])
+	}
+	switch buf[1] {
+	case method:
+	case socks5AuthNoAccept:
+		return errors.New("no acceptable authentication methods")
+	default:
+		return fmt.Errorf("unexpected authentication method %d", buf[1])
+	}
+
+	if method == socks5AuthPassword {
+		if err = d.authenticate(conn); err != nil {
+			return err
+		}
+	}
+
+	req := []byte{socks5Version, socks5CmdConnect, 0}
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return fmt.Errorf("host name %q is too long", host)
+		}
+		req = append(req, socks5AddrDomain, byte(len(host)))
+		req = append(req, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(req, socks5AddrIPv4)
+		req = append(req, ip4...)
+	} else {
+		req = append(req, socks5AddrIPv6)
+		req = append(req, ip.To16()...)
+	}
+	req = append(req, 0, 0)
+	binary.BigEndian.PutUint16(req[len(req)-2:], uint16(port))
+	if _, err = conn.Write(req); err != nil {
+		return err
+	}
+
+	// reply: VER REP RSV ATYP BND.ADDR BND.PORT
+	reply := make([]byte, 4)
+	if _, err = io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[1] != 0 {
+		return SOCKS5ReplyError(reply[1])
+	}
+
+	var addrLen int
+	switch reply[3] {
+	case socks5AddrIPv4:
+		addrLen = net.IPv4len
+	case socks5AddrIPv6:
+		addrLen = net.IPv6len
+	case socks5AddrDomain:
+		if _, err = io.ReadFull(conn, reply[:1]); err != nil {
+			return err
+		}
+		addrLen = int(reply[0])
+	default:
+		return fmt.Errorf("unexpected address type %d", reply[3])
+	}
+
+	_, err = io.ReadFull(conn, make([]byte, addrLen+2))
+	return err
+}
+
+func (d *SOCKS5Dialer) authenticate(conn net.Conn) error {
+	if len(d.Username) > 255 || len(d.Password) > 255 {
+		return errors.New("username or password is too long")
+	}
+
+	req := []byte{0x01, byte(len(d.Username))}
+	req = append(req, d.Username...)
+	req = append(req, byte(len(d.Password)))
+	req = append(req, d.Password...)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	rsp := make([]byte, 2)
+	if _, err := io.ReadFull(conn, rsp); err != nil {
+		return err
+	}
+	if rsp[1] != 0 {
+		return ErrSOCKS5AuthFailed
+	}
+	return nil
+}
+
+func checkNetwork(network string) error {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return nil
+	default:
+		return fmt.Errorf("network %q is not supported", network)
+	}
+}
@@ -0,0 +1,372 @@
+package cgminer
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// RepairRule fixes specific kind of malformed JSON produced by miner firmware
+type RepairRule interface {
+	// Name returns rule name reported to repair hook
+	Name() string
+
+	// Repair returns fixed response and reports whether anything was changed
+	Repair(cmd Command, data []byte) ([]byte, bool)
+}
+
+// RepairFunc is an adapter to allow the use of ordinary functions as RepairRule
+type RepairFunc struct {
+	RuleName string
+	Func     func(cmd Command, data []byte) ([]byte, bool)
+}
+
+// Name implements RepairRule
+func (r RepairFunc) Name() string {
+	return r.RuleName
+}
+
+// Repair implements RepairRule
+func (r RepairFunc) Repair(cmd Command, data []byte) ([]byte, bool) {
+	return r.Func(cmd, data)
+}
+
+// Repair rules
+var (
+	// RepairStatsConcat merges adjacent objects in "stats" response ("}{" to ",")
+	RepairStatsConcat RepairRule = RepairFunc{RuleName: "stats-concat", Func: repairStatsConcat}
+
+	// RepairControlChars escapes raw control characters in strings
+	RepairControlChars RepairRule = RepairFunc{RuleName: "control-chars", Func: repairControlChars}
+
+	// RepairNonFinite replaces "nan" and "inf" literals with null
+	RepairNonFinite RepairRule = RepairFunc{RuleName: "non-finite", Func: repairNonFinite}
+
+	// RepairMissingCommas inserts missing commas between objects, arrays and keys
+	RepairMissingCommas RepairRule = RepairFunc{RuleName: "missing-commas", Func: repairMissingCommas}
+
+	// RepairTrailingCommas removes commas before closing brackets
+	RepairTrailingCommas RepairRule = RepairFunc{RuleName: "trailing-commas", Func: repairTrailingCommas}
+
+	// RepairDuplicateKeys renames duplicated object keys ("key", "key_2", ...),
+	// so the first value is decoded into struct field.
+	RepairDuplicateKeys RepairRule = RepairFunc{RuleName: "duplicate-keys", Func: repairDuplicateKeys}
+)
+
+// DefaultRepairRules is list of rules used by NewTolerantJSONTransport.
+//
+// Order matters: string contents are fixed first, then structure.
+var DefaultRepairRules = []RepairRule{
+	RepairStatsConcat,
+	RepairControlChars,
+	RepairNonFinite,
+	RepairMissingCommas,
+	RepairTrailingCommas,
+	RepairDuplicateKeys,
+}
+
+// Repairer applies list of repair rules to API response before decoding
+type Repairer struct {
+	// Rules is list of rules applied in order
+	Rules []RepairRule
+
+	// OnRepair is optional debug hook called with names of applied rules
+	// when at least one rule changed the response.
+	OnRepair func(cmd Command, applied []string)
+}
+
+// NewRepairer returns repairer with passed rules
+func NewRepairer(rules ...RepairRule) *Repairer {
+	return &Repairer{Rules: rules}
+}
+
+// Repair applies rules to response and returns fixed response with
+// names of applied rules.
+func (r *Repairer) Repair(cmd Command, data []byte) ([]byte, []string) {
+	var applied []string
+	for _, rule := range r.Rules {
+		var changed bool
+		if data, changed = rule.Repair(cmd, data); changed {
+			applied = append(applied, rule.Name())
+		}
+	}
+
+	if len(applied) > 0 && r.OnRepair != nil {
+		r.OnRepair(cmd, applied)
+	}
+	return data, applied
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// nextNonSpace returns index of next non-whitespace character starting from i
+// or len(data)
+func nextNonSpace(data []byte, i int) int {
+	for i < len(data) && isSpace(data[i]) {
+		i++
+	}
+	return i
+}
+
+// prevNonSpace returns last non-whitespace character in buffer or 0
+func prevNonSpace(buf []byte) byte {
+	for i := len(buf) - 1; i >= 0; i-- {
+		if !isSpace(buf[i]) {
+			return buf[i]
+		}
+	}
+	return 0
+}
+
+// stringEnd returns index of closing quote of string literal starting at i
+// or len(data) if string is not terminated
+func stringEnd(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(data)
+}
+
+func repairStatsConcat(cmd Command, data []byte) ([]byte, bool) {
+	if cmd.Command != "stats" || !bytes.Contains(data, []byte("}{")) {
+		return data, false
+	}
+	return bytes.Replace(data, []byte("}{"), []byte(","), 1), true
+}
+
+func repairControlChars(_ Command, data []byte) ([]byte, bool) {
+	var (
+		out      bytes.Buffer
+		changed  bool
+		inString bool
+	)
+	out.Grow(len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString && c == '\\' && i+1 < len(data):
+			out.WriteByte(c)
+			i++
+			c = data[i]
+		case c == '"':
+			inString = !inString
+		case inString && c < 0x20:
+			changed = true
+			switch c {
+			case '\n':
+				out.WriteString(`\n`)
+			case '\r':
+				out.WriteString(`\r`)
+			case '\t':
+				out.WriteString(`\t`)
+			default:
+				fmt.Fprintf(&out, `\u%04x`, c)
+			}
+			continue
+		}
+		out.WriteByte(c)
+	}
+
+	if !changed {
+		return data, false
+	}
+	return out.Bytes(), true
+}
+
+var nonFiniteLiterals = [][]byte{
+	[]byte("-infinity"), []byte("infinity"),
+	[]byte("-nan"), []byte("nan"),
+	[]byte("-inf"), []byte("inf"),
+}
+
+func repairNonFinite(_ Command, data []byte) ([]byte, bool) {
+	var (
+		out     bytes.Buffer
+		changed bool
+	)
+	out.Grow(len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c == '"' {
+			end := stringEnd(data, i)
+			if end < len(data) {
+				end++
+			}
+			out.Write(data[i:end])
+			i = end - 1
+			continue
+		}
+
+		// literal can start only at value position
+		if prev := prevNonSpace(out.Bytes()); prev == ':' || prev == ',' || prev == '[' {
+			if n := matchNonFinite(data[i:]); n > 0 {
+				out.WriteString("null")
+				i += n - 1
+				changed = true
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+
+	if !changed {
+		return data, false
+	}
+	return out.Bytes(), true
+}
+
+// matchNonFinite returns length of non-finite literal at the beginning of data
+func matchNonFinite(data []byte) int {
+	for _, lit := range nonFiniteLiterals {
+		if len(data) < len(lit) || !bytes.EqualFold(data[:len(lit)], lit) {
+			continue
+		}
+
+		// literal should end at delimiter
+		if len(data) == len(lit) {
+			return len(lit)
+		}
+		switch c := data[len(lit)]; {
+		case c == ',' || c == '}' || c == ']' || isSpace(c):
+			return len(lit)
+		}
+	}
+	return 0
+}
+
+func repairMissingCommas(_ Command, data []byte) ([]byte, bool) {
+	var (
+		out     bytes.Buffer
+		changed bool
+	)
+	out.Grow(len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c == '"' {
+			end := stringEnd(data, i)
+			if end < len(data) {
+				end++
+			}
+			out.Write(data[i:end])
+			i = end - 1
+			continue
+		}
+
+		out.WriteByte(c)
+		if c != '}' && c != ']' {
+			continue
+		}
+
+		if next := nextNonSpace(data, i+1); next < len(data) {
+			switch data[next] {
+			case '{', '[', '"':
+				out.WriteByte(',')
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return data, false
+	}
+	return out.Bytes(), true
+}
+
+func repairTrailingCommas(_ Command, data []byte) ([]byte, bool) {
+	var (
+		out     bytes.Buffer
+		changed bool
+	)
+	out.Grow(len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c == '"' {
+			end := stringEnd(data, i)
+			if end < len(data) {
+				end++
+			}
+			out.Write(data[i:end])
+			i = end - 1
+			continue
+		}
+
+		if c == ',' {
+			if next := nextNonSpace(data, i+1); next < len(data) && (data[next] == '}' || data[next] == ']') {
+				changed = true
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+
+	if !changed {
+		return data, false
+	}
+	return out.Bytes(), true
+}
+
+func repairDuplicateKeys(_ Command, data []byte) ([]byte, bool) {
+	var (
+		out     bytes.Buffer
+		changed bool
+		// scopes holds seen keys per object, nil for arrays
+		scopes []map[string]int
+	)
+	out.Grow(len(data))
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case '{':
+			scopes = append(scopes, make(map[string]int))
+		case '[':
+			scopes = append(scopes, nil)
+		case '}', ']':
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+		case '"':
+			end := stringEnd(data, i)
+			if end == len(data) {
+				out.Write(data[i:])
+				i = end
+				continue
+			}
+
+			lit := data[i : end+1]
+			next := nextNonSpace(data, end+1)
+			isKey := next < len(data) && data[next] == ':' && len(scopes) > 0 && scopes[len(scopes)-1] != nil
+			if !isKey {
+				out.Write(lit)
+				i = end
+				continue
+			}
+
+			seen := scopes[len(scopes)-1]
+			key := string(lit)
+			seen[key]++
+			if n := seen[key]; n > 1 {
+				out.Write(lit[:len(lit)-1])
+				out.WriteString("_" + strconv.Itoa(n))
+				out.WriteByte('"')
+				changed = true
+			} else {
+				out.Write(lit)
+			}
+			i = end
+			continue
+		}
+		out.WriteByte(c)
+	}
+
+	if !changed {
+		return data, false
+	}
+	return out.Bytes(), true
+}
@@ -0,0 +1,85 @@
+package cgminer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestRepairRules(t *testing.T) {
+	for _, rule := range DefaultRepairRules {
+		rule := rule
+		t.Run(rule.Name(), func(t *testing.T) {
+			input, err := ioutil.ReadFile(path.Join("testdata", "repair", rule.Name()+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := ioutil.ReadFile(path.Join("testdata", "repair", rule.Name()+".golden.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := NewCommandWithoutParameter("stats")
+			got, changed := rule.Repair(cmd, input)
+			if !changed {
+				t.Fatal("rule was not applied")
+			}
+			if string(got) != string(want) {
+				t.Fatalf("result mismatch:\ngot:  %s\nwant: %s", got, want)
+			}
+			if !json.Valid(got) {
+				t.Fatalf("result is not valid JSON: %s", got)
+			}
+
+			// rule should not change valid response
+			if _, changed := rule.Repair(cmd, want); changed {
+				t.Fatal("rule changed already valid response")
+			}
+		})
+	}
+}
+
+func TestRepairStatsConcat_OtherCommands(t *testing.T) {
+	input := []byte(`[{"a":1}{"b":2}]`)
+	if _, changed := RepairStatsConcat.Repair(NewCommandWithoutParameter("devs"), input); changed {
+		t.Fatal("rule should be applied only to stats command")
+	}
+}
+
+func TestTolerantJSONTransport(t *testing.T) {
+	payload := []byte(`{"STATUS":[{"STATUS":"S","When":1650000000,"Code":9,"Msg":"2 GPU(s)",},],` +
+		`"DEVS":[{"GPU":0,"Temperature":61.5,"MHS av":30.1,"Temperature":0}{"GPU":1,"Temperature":nan,"MHS av":29.9,}],"id":1}` + "\x00")
+	expected := []Devs{
+		{GPU: 0, Temperature: 61.5, MHSav: 30.1},
+		{GPU: 1, MHSav: 29.9},
+	}
+
+	var applied []string
+	ctx, finish := context.WithCancel(context.Background())
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, payload)
+	wait(1)
+	miner := NewCGMiner(ip, port, minerTimeout)
+	miner.Transport = NewTolerantJSONTransport(func(cmd Command, rules []string) {
+		if cmd.Command != "devs" {
+			t.Errorf("unexpected command %q", cmd.Command)
+		}
+		applied = rules
+	})
+	devs, err := miner.Devs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(*devs, expected); diff != nil {
+		t.Error(diff)
+	}
+	if diff := deep.Equal(applied, []string{"non-finite", "missing-commas", "trailing-commas", "duplicate-keys"}); diff != nil {
+		t.Error(diff)
+	}
+	finish()
+	wait(1)
+}
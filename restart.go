@@ -0,0 +1,358 @@
+package cgminer
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultRestartTolerance is default allowed difference between wall clock
+// and Elapsed counter growth between two polls.
+const DefaultRestartTolerance = 30 * time.Second
+
+// RestartEventType is type of event reported by RestartTracker
+type RestartEventType int
+
+const (
+	// MinerRestarted is reported when miner Elapsed counter went backwards
+	MinerRestarted RestartEventType = iota + 1
+
+	// DeviceReset is reported when device Elapsed counter went backwards,
+	// while miner kept running
+	DeviceReset
+
+	// CountersZeroed is reported when share counters went backwards
+	// without miner restart, e.g. after "zero" command
+	CountersZeroed
+)
+
+// String implements fmt.Stringer
+func (t RestartEventType) String() string {
+	switch t {
+	case MinerRestarted:
+		return "MinerRestarted"
+	case DeviceReset:
+		return "DeviceReset"
+	case CountersZeroed:
+		return "CountersZeroed"
+	default:
+		return "Unknown"
+	}
+}
+
+// Counters holds cumulative share counters
+type Counters struct {
+	Accepted           int64
+	Rejected           int64
+	Stale              int64
+	HardwareErrors     int64
+	GetFailures        int64
+	DifficultyAccepted float64
+	DifficultyRejected float64
+	DifficultyStale    float64
+	Diff1Work          float64
+}
+
+// SummaryCounters returns share counters of Summary
+func SummaryCounters(s Summary) Counters {
+	return Counters{
+		Accepted:           s.Accepted,
+		Rejected:           s.Rejected,
+		Stale:              s.Stale,
+		HardwareErrors:     s.HardwareErrors,
+		GetFailures:        s.GetFailures,
+		DifficultyAccepted: s.DifficultyAccepted,
+		DifficultyRejected: s.DifficultyRejected,
+		DifficultyStale:    s.DifficultyStale,
+	}
+}
+
+// DevsCounters returns share counters of device
+func DevsCounters(d Devs) Counters {
+	return Counters{
+		Accepted:           d.Accepted,
+		Rejected:           d.Rejected,
+		HardwareErrors:     d.HardwareErrors,
+		DifficultyAccepted: d.DifficultyAccepted,
+		DifficultyRejected: d.DifficultyRejected,
+		Diff1Work:          d.Diff1Work,
+	}
+}
+
+// PoolCounters returns share counters of pool
+func PoolCounters(p Pool) Counters {
+	return Counters{
+		Accepted:           p.Accepted,
+		Rejected:           p.Rejected,
+		Stale:              p.Stale,
+		GetFailures:        p.GetFailures,
+		DifficultyAccepted: p.DifficultyAccepted,
+		DifficultyRejected: p.DifficultyRejected,
+		DifficultyStale:    p.DifficultyStale,
+		Diff1Work:          p.Diff1Shares,
+	}
+}
+
+// Add returns sum of counters
+func (c Counters) Add(o Counters) Counters {
+	return Counters{
+		Accepted:           c.Accepted + o.Accepted,
+		Rejected:           c.Rejected + o.Rejected,
+		Stale:              c.Stale + o.Stale,
+		HardwareErrors:     c.HardwareErrors + o.HardwareErrors,
+		GetFailures:        c.GetFailures + o.GetFailures,
+		DifficultyAccepted: c.DifficultyAccepted + o.DifficultyAccepted,
+		DifficultyRejected: c.DifficultyRejected + o.DifficultyRejected,
+		DifficultyStale:    c.DifficultyStale + o.DifficultyStale,
+		Diff1Work:          c.Diff1Work + o.Diff1Work,
+	}
+}
+
+// Sub returns difference of counters
+func (c Counters) Sub(o Counters) Counters {
+	return Counters{
+		Accepted:           c.Accepted - o.Accepted,
+		Rejected:           c.Rejected - o.Rejected,
+		Stale:              c.Stale - o.Stale,
+		HardwareErrors:     c.HardwareErrors - o.HardwareErrors,
+		GetFailures:        c.GetFailures - o.GetFailures,
+		DifficultyAccepted: c.DifficultyAccepted - o.DifficultyAccepted,
+		DifficultyRejected: c.DifficultyRejected - o.DifficultyRejected,
+		DifficultyStale:    c.DifficultyStale - o.DifficultyStale,
+		Diff1Work:          c.Diff1Work - o.Diff1Work,
+	}
+}
+
+// Decreased reports whether any counter is less than in previous value
+func (c Counters) Decreased(prev Counters) bool {
+	return c.Accepted < prev.Accepted ||
+		c.Rejected < prev.Rejected ||
+		c.Stale < prev.Stale ||
+		c.HardwareErrors < prev.HardwareErrors ||
+		c.GetFailures < prev.GetFailures ||
+		c.DifficultyAccepted < prev.DifficultyAccepted ||
+		c.DifficultyRejected < prev.DifficultyRejected ||
+		c.DifficultyStale < prev.DifficultyStale ||
+		c.Diff1Work < prev.Diff1Work
+}
+
+// Poll is result of single miner poll consumed by RestartTracker.
+//
+// Summary and Stats are optional, Elapsed is taken from Summary if present.
+type Poll struct {
+	// Time is poll time
+	Time time.Time
+
+	Summary *Summary
+	Stats   *GenericStats
+	Devs    []Devs
+}
+
+// elapsed returns miner Elapsed counter
+func (p Poll) elapsed() (int64, bool) {
+	if p.Summary != nil {
+		return p.Summary.Elapsed, true
+	}
+	if p.Stats != nil {
+		return p.Stats.Elapsed, true
+	}
+	return 0, false
+}
+
+// RestartEvent is reported by RestartTracker
+type RestartEvent struct {
+	Type RestartEventType
+
+	// Miner is miner name passed to RestartTracker.Observe
+	Miner string
+
+	// GPU is device index for DeviceReset events
+	GPU int64
+
+	// Time is time of poll which detected event
+	Time time.Time
+
+	// StartedAt is estimated time of restart, zero for CountersZeroed events
+	StartedAt time.Time
+
+	// PrevElapsed is Elapsed counter reported by previous poll
+	PrevElapsed int64
+
+	// Elapsed is Elapsed counter reported by current poll
+	Elapsed int64
+
+	// Totals is last observed counters before reset
+	Totals Counters
+}
+
+type deviceState struct {
+	elapsed  int64
+	counters Counters
+}
+
+type minerState struct {
+	seen       time.Time
+	elapsed    int64
+	hasElapsed bool
+	counters   Counters
+	hasSum     bool
+	devs       map[int64]deviceState
+
+	// carried is sum of counters before resets
+	carried Counters
+}
+
+// RestartTracker detects miner restarts, device resets and counter resets
+// from successive polls.
+//
+// RestartTracker is safe for concurrent use.
+type RestartTracker struct {
+	// Tolerance is allowed difference between wall clock time passed
+	// between polls and Elapsed counter growth. Restart is reported when
+	// Elapsed grew less than expected, even if it didn't go backwards.
+	//
+	// DefaultRestartTolerance is used if zero.
+	Tolerance time.Duration
+
+	mu     sync.Mutex
+	miners map[string]*minerState
+}
+
+// NewRestartTracker returns new RestartTracker
+func NewRestartTracker() *RestartTracker {
+	return &RestartTracker{Tolerance: DefaultRestartTolerance}
+}
+
+// Observe consumes miner poll and returns detected events.
+//
+// First poll of miner never produces events.
+func (t *RestartTracker) Observe(miner string, poll Poll) []RestartEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.miners == nil {
+		t.miners = make(map[string]*minerState)
+	}
+	if poll.Time.IsZero() {
+		poll.Time = time.Now()
+	}
+
+	state, ok := t.miners[miner]
+	if !ok {
+		state = &minerState{devs: make(map[int64]deviceState)}
+		t.miners[miner] = state
+	}
+
+	var events []RestartEvent
+	restarted := false
+	elapsed, hasElapsed := poll.elapsed()
+	if hasElapsed && state.hasElapsed && t.elapsedReset(state.elapsed, elapsed, poll.Time.Sub(state.seen)) {
+		restarted = true
+		events = append(events, RestartEvent{
+			Type:        MinerRestarted,
+			Miner:       miner,
+			Time:        poll.Time,
+			StartedAt:   poll.Time.Add(-time.Duration(elapsed) * time.Second),
+			PrevElapsed: state.elapsed,
+			Elapsed:     elapsed,
+			Totals:      state.counters,
+		})
+	}
+
+	if poll.Summary != nil {
+		counters := SummaryCounters(*poll.Summary)
+		if state.hasSum && !restarted && counters.Decreased(state.counters) {
+			restarted = true
+			events = append(events, RestartEvent{
+				Type:        CountersZeroed,
+				Miner:       miner,
+				Time:        poll.Time,
+				PrevElapsed: state.elapsed,
+				Elapsed:     elapsed,
+				Totals:      state.counters,
+			})
+		}
+		if restarted {
+			state.carried = state.carried.Add(state.counters)
+		}
+		state.counters = counters
+		state.hasSum = true
+	}
+
+	devs := make(map[int64]deviceState, len(poll.Devs))
+	for _, d := range poll.Devs {
+		cur := deviceState{elapsed: d.DeviceElapsed, counters: DevsCounters(d)}
+		devs[d.GPU] = cur
+
+		prev, ok := state.devs[d.GPU]
+		if !ok || restarted || cur.elapsed >= prev.elapsed {
+			continue
+		}
+		events = append(events, RestartEvent{
+			Type:        DeviceReset,
+			Miner:       miner,
+			GPU:         d.GPU,
+			Time:        poll.Time,
+			StartedAt:   poll.Time.Add(-time.Duration(cur.elapsed) * time.Second),
+			PrevElapsed: prev.elapsed,
+			Elapsed:     cur.elapsed,
+			Totals:      prev.counters,
+		})
+	}
+	state.devs = devs
+
+	if hasElapsed {
+		state.elapsed = elapsed
+		state.hasElapsed = true
+	}
+	state.seen = poll.Time
+	return events
+}
+
+// elapsedReset reports whether Elapsed counter indicates restart
+func (t *RestartTracker) elapsedReset(prev, cur int64, wall time.Duration) bool {
+	if cur < prev {
+		return true
+	}
+
+	tolerance := t.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultRestartTolerance
+	}
+	grown := time.Duration(cur-prev) * time.Second
+	return grown+tolerance < wall
+}
+
+// Uptime returns miner uptime reported by last poll
+func (t *RestartTracker) Uptime(miner string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.miners[miner]
+	if !ok || !state.hasElapsed {
+		return 0, false
+	}
+	return time.Duration(state.elapsed) * time.Second, true
+}
+
+// Totals returns miner share counters accumulated across restarts
+// and counter resets.
+//
+// Unlike raw Summary counters, Totals never decrease, so rates derived
+// from them are not affected by resets.
+func (t *RestartTracker) Totals(miner string) (Counters, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.miners[miner]
+	if !ok || !state.hasSum {
+		return Counters{}, false
+	}
+	return state.carried.Add(state.counters), true
+}
+
+// Forget removes miner state
+func (t *RestartTracker) Forget(miner string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.miners, miner)
+}
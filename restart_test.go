@@ -0,0 +1,93 @@
+package cgminer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartTracker(t *testing.T) {
+	tracker := NewRestartTracker()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	poll := func(offset, elapsed, accepted int64, devElapsed ...int64) Poll {
+		p := Poll{
+			Time:    start.Add(time.Duration(offset) * time.Second),
+			Summary: &Summary{Elapsed: elapsed, Accepted: accepted, DifficultyAccepted: float64(accepted) * 10},
+		}
+		for i, e := range devElapsed {
+			p.Devs = append(p.Devs, Devs{GPU: int64(i), DeviceElapsed: e, Accepted: accepted})
+		}
+		return p
+	}
+
+	steps := []struct {
+		name   string
+		poll   Poll
+		events []RestartEventType
+	}{
+		{"first poll", poll(0, 1000, 50, 1000, 1000), nil},
+		{"normal", poll(60, 1060, 60, 1060, 1060), nil},
+		{"device reset", poll(120, 1120, 70, 1120, 30), []RestartEventType{DeviceReset}},
+		{"zeroed", poll(180, 1180, 2, 1180, 90), []RestartEventType{CountersZeroed}},
+		{"restart", poll(240, 20, 1, 20, 20), []RestartEventType{MinerRestarted}},
+		{"restart during gap", poll(900, 200, 5, 200, 200), []RestartEventType{MinerRestarted}},
+		{"normal after restart", poll(960, 260, 8, 260, 260), nil},
+	}
+
+	var all []RestartEvent
+	for _, step := range steps {
+		events := tracker.Observe("m1", step.poll)
+		if len(events) != len(step.events) {
+			t.Fatalf("%s: expected %v, got %+v", step.name, step.events, events)
+		}
+		for i, e := range events {
+			if e.Type != step.events[i] {
+				t.Fatalf("%s: expected %s, got %s", step.name, step.events[i], e.Type)
+			}
+		}
+		all = append(all, events...)
+	}
+
+	reset := all[0]
+	if reset.GPU != 1 || reset.PrevElapsed != 1060 || reset.Elapsed != 30 || reset.Totals.Accepted != 60 {
+		t.Errorf("unexpected device reset event: %+v", reset)
+	}
+	if want := start.Add(90 * time.Second); !reset.StartedAt.Equal(want) {
+		t.Errorf("expected device start at %s, got %s", want, reset.StartedAt)
+	}
+
+	restart := all[2]
+	if restart.Totals.Accepted != 2 || restart.PrevElapsed != 1180 || restart.Elapsed != 20 {
+		t.Errorf("unexpected restart event: %+v", restart)
+	}
+	if want := start.Add(220 * time.Second); !restart.StartedAt.Equal(want) {
+		t.Errorf("expected miner start at %s, got %s", want, restart.StartedAt)
+	}
+
+	// 70 before zeroing + 2 before restart + 1 before second restart + 8
+	totals, ok := tracker.Totals("m1")
+	if !ok || totals.Accepted != 81 || totals.DifficultyAccepted != 810 {
+		t.Errorf("unexpected totals: %+v", totals)
+	}
+
+	uptime, ok := tracker.Uptime("m1")
+	if !ok || uptime != 260*time.Second {
+		t.Errorf("unexpected uptime: %s", uptime)
+	}
+
+	tracker.Forget("m1")
+	if _, ok := tracker.Uptime("m1"); ok {
+		t.Error("miner state should be removed")
+	}
+}
+
+func TestRestartTracker_StatsElapsed(t *testing.T) {
+	tracker := NewRestartTracker()
+	now := time.Now()
+
+	tracker.Observe("m1", Poll{Time: now, Stats: &GenericStats{Elapsed: 500}})
+	events := tracker.Observe("m1", Poll{Time: now.Add(time.Minute), Stats: &GenericStats{Elapsed: 10}})
+	if len(events) != 1 || events[0].Type != MinerRestarted || events[0].Miner != "m1" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
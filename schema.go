@@ -0,0 +1,265 @@
+package cgminer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RequiredFields holds keys which are expected to be present in response
+// section for specific response type.
+//
+// Used to report missing keys in SchemaDriftError. Types without
+// entry are checked only for unknown keys and type mismatches.
+var RequiredFields = map[reflect.Type][]string{
+	reflect.TypeOf(Summary{}): {
+		"Elapsed", "Accepted", "Rejected", "Hardware Errors", "Stale",
+		"Difficulty Accepted", "Difficulty Rejected", "Get Failures",
+	},
+	reflect.TypeOf(Devs{}): {
+		"GPU", "Enabled", "Status", "Temperature", "MHS av", "Accepted",
+		"Rejected", "Hardware Errors", "Difficulty Accepted", "Device Elapsed",
+	},
+	reflect.TypeOf(Pool{}): {
+		"POOL", "URL", "Status", "Priority", "Accepted", "Rejected", "Stale",
+		"Difficulty Accepted", "Last Share Time", "User",
+	},
+	reflect.TypeOf(GenericStats{}): {
+		"STATS", "Elapsed",
+	},
+}
+
+// FieldMismatch describes response value which doesn't match field type
+type FieldMismatch struct {
+	// Field is response key
+	Field string
+
+	// Expected is Go type of struct field
+	Expected string
+
+	// Got is JSON type of response value
+	Got string
+}
+
+// String implements fmt.Stringer
+func (m FieldMismatch) String() string {
+	return fmt.Sprintf("%s (expected %s, got %s)", m.Field, m.Expected, m.Got)
+}
+
+// SchemaDrift is difference between response section and response type
+type SchemaDrift struct {
+	// Type is response struct name, e.g. "Summary"
+	Type string
+
+	// Section is response section name, e.g. "SUMMARY"
+	Section string
+
+	// Unknown is list of keys which have no corresponding struct field
+	Unknown []string
+
+	// Missing is list of required keys absent in response
+	Missing []string
+
+	// Mismatches is list of values which cannot be decoded into struct field
+	Mismatches []FieldMismatch
+}
+
+// SchemaDriftError is returned by strict JSONTransport when response
+// doesn't match expected response type.
+type SchemaDriftError struct {
+	// Command is API command name
+	Command string
+
+	// Drifts is list of differences per response section
+	Drifts []SchemaDrift
+}
+
+// Error implements error
+func (err *SchemaDriftError) Error() string {
+	parts := make([]string, 0, len(err.Drifts))
+	for _, d := range err.Drifts {
+		var details []string
+		if len(d.Unknown) > 0 {
+			details = append(details, "unknown: "+strings.Join(d.Unknown, ", "))
+		}
+		if len(d.Missing) > 0 {
+			details = append(details, "missing: "+strings.Join(d.Missing, ", "))
+		}
+		for _, m := range d.Mismatches {
+			details = append(details, "mismatch: "+m.String())
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s]", d.Type, strings.Join(details, "; ")))
+	}
+	return fmt.Sprintf("schema drift in %q response: %s", err.Command, strings.Join(parts, ", "))
+}
+
+// checkSchema compares raw response with response struct and
+// returns nil if there is no drift.
+func checkSchema(cmd Command, rsp []byte, out interface{}) *SchemaDriftError {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(rsp, &sections); err != nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(out)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var drifts []SchemaDrift
+	for _, f := range sectionFields(t) {
+		name := fieldName(f)
+		raw, ok := sections[name]
+		if !ok {
+			continue
+		}
+
+		var records []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &records); err != nil {
+			continue
+		}
+
+		if d := checkSection(f.Type.Elem(), name, records); d != nil {
+			drifts = append(drifts, *d)
+		}
+	}
+
+	if len(drifts) == 0 {
+		return nil
+	}
+	return &SchemaDriftError{Command: cmd.Command, Drifts: drifts}
+}
+
+// sectionFields returns response struct fields which hold slice of records
+func sectionFields(t reflect.Type) []reflect.StructField {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		switch {
+		case f.Anonymous:
+			fields = append(fields, sectionFields(f.Type)...)
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct && f.PkgPath == "":
+			// Status section is validated by HasError
+			if f.Type.Elem() != reflect.TypeOf(Status{}) {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+func checkSection(t reflect.Type, section string, records []map[string]json.RawMessage) *SchemaDrift {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name := fieldName(f); name != "" && f.PkgPath == "" {
+			fields[name] = f
+		}
+	}
+
+	unknown := make(map[string]struct{})
+	missing := make(map[string]struct{})
+	mismatches := make(map[string]FieldMismatch)
+	for _, record := range records {
+		for key, val := range record {
+			f, ok := fields[key]
+			if !ok {
+				unknown[key] = struct{}{}
+				continue
+			}
+
+			if _, seen := mismatches[key]; !seen && !valueFits(f, val) {
+				mismatches[key] = FieldMismatch{Field: key, Expected: f.Type.String(), Got: jsonKind(val)}
+			}
+		}
+
+		for _, key := range RequiredFields[t] {
+			if _, ok := record[key]; !ok {
+				missing[key] = struct{}{}
+			}
+		}
+	}
+
+	if len(unknown) == 0 && len(missing) == 0 && len(mismatches) == 0 {
+		return nil
+	}
+
+	d := &SchemaDrift{
+		Type:    t.Name(),
+		Section: section,
+		Unknown: sortedKeys(unknown),
+		Missing: sortedKeys(missing),
+	}
+	for _, key := range sortedMismatchKeys(mismatches) {
+		d.Mismatches = append(d.Mismatches, mismatches[key])
+	}
+	return d
+}
+
+// valueFits reports whether raw value can be decoded into struct field
+func valueFits(f reflect.StructField, raw json.RawMessage) bool {
+	if strings.Contains(f.Tag.Get("json"), ",string") {
+		// value is JSON-encoded inside of string
+		var s string
+		if json.Unmarshal(raw, &s) != nil {
+			return string(raw) == "null"
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil && f.Type.Kind() != reflect.String && f.Type.Kind() != reflect.Bool {
+			return false
+		}
+		return true
+	}
+
+	return json.Unmarshal(raw, reflect.New(f.Type).Interface()) == nil
+}
+
+func jsonKind(raw json.RawMessage) string {
+	s := strings.TrimSpace(string(raw))
+	if s == "" {
+		return "empty"
+	}
+
+	switch s[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedMismatchKeys(m map[string]FieldMismatch) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
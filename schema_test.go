@@ -0,0 +1,177 @@
+package cgminer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+const (
+	summaryOK = `{"STATUS":[{"STATUS":"S","When":1650000000,"Code":11,"Msg":"Summary"}],"SUMMARY":[{` +
+		`"Elapsed":3600,"Accepted":512,"Rejected":1,"Hardware Errors":0,"Stale":0,` +
+		`"Difficulty Accepted":1024.0,"Difficulty Rejected":2.0,"Get Failures":0}],"id":1}`
+	summaryDrift = `{"STATUS":[{"STATUS":"S","When":1650000000,"Code":11,"Msg":"Summary"}],"SUMMARY":[{` +
+		`"Elapsed":3600,"Accepted":"512","Rejected":1,"Hardware Errors":0,` +
+		`"Difficulty Accepted":1024.0,"Difficulty Rejected":2.0,"Get Failures":0,"Pool Shares":513,"KHS av":30000}],"id":1}`
+)
+
+var expectedSummaryDrift = &SchemaDriftError{
+	Command: "summary",
+	Drifts: []SchemaDrift{
+		{
+			Type:       "Summary",
+			Section:    "SUMMARY",
+			Unknown:    []string{"KHS av", "Pool Shares"},
+			Missing:    []string{"Stale"},
+			Mismatches: []FieldMismatch{{Field: "Accepted", Expected: "int64", Got: "string"}},
+		},
+	},
+}
+
+func callMiner(t *testing.T, transport Transport, payload string, fn func(miner *CGMiner) error) error {
+	ctx, finish := context.WithCancel(context.Background())
+	defer func() {
+		finish()
+		wait(1)
+	}()
+
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, append([]byte(payload), 0x00))
+	wait(1)
+	miner := NewCGMiner(ip, port, minerTimeout)
+	miner.Transport = transport
+	return fn(miner)
+}
+
+func callSummary(t *testing.T, transport Transport, payload string) (summary *Summary, err error) {
+	err = callMiner(t, transport, payload, func(miner *CGMiner) error {
+		summary, err = miner.Summary()
+		return err
+	})
+	return summary, err
+}
+
+func TestStrictJSONTransport(t *testing.T) {
+	if _, err := callSummary(t, JSONTransport{Schema: &SchemaOptions{Strict: true}}, summaryOK); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := callSummary(t, JSONTransport{Schema: &SchemaOptions{Strict: true}}, summaryDrift)
+	var drift *SchemaDriftError
+	if !errors.As(err, &drift) {
+		t.Fatalf("expected SchemaDriftError, got %v", err)
+	}
+	if diff := deep.Equal(drift, expectedSummaryDrift); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestLenientSchemaDriftHook(t *testing.T) {
+	var reported *SchemaDriftError
+	transport := JSONTransport{Schema: &SchemaOptions{OnSchemaDrift: func(drift *SchemaDriftError) {
+		reported = drift
+	}}}
+
+	summary, err := callSummary(t, transport, summaryDrift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Elapsed != 3600 || summary.Rejected != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if diff := deep.Equal(reported, expectedSummaryDrift); diff != nil {
+		t.Error(diff)
+	}
+
+	reported = nil
+	if _, err := callSummary(t, transport, summaryOK); err != nil {
+		t.Fatal(err)
+	}
+	if reported != nil {
+		t.Fatalf("unexpected drift: %v", reported)
+	}
+}
+
+func TestSchemaDrift_Sections(t *testing.T) {
+	const status = `"STATUS":[{"STATUS":"S","When":1650000000,"Code":9,"Msg":"OK"}],"id":1`
+	cases := map[string]struct {
+		payload string
+		call    func(miner *CGMiner) error
+		fails   bool
+		drift   SchemaDrift
+	}{
+		"devs": {
+			payload: `{` + status + `,"DEVS":[{"GPU":0,"Enabled":"Y","Status":"Alive","Temperature":"61.0",` +
+				`"MHS av":30.1,"Accepted":10,"Rejected":0,"Hardware Errors":0,"Difficulty Accepted":1.0,"Fan RPM":1800}]}`,
+			call: func(miner *CGMiner) error {
+				_, err := miner.Devs()
+				return err
+			},
+			fails: true,
+			drift: SchemaDrift{
+				Type:       "Devs",
+				Section:    "DEVS",
+				Unknown:    []string{"Fan RPM"},
+				Missing:    []string{"Device Elapsed"},
+				Mismatches: []FieldMismatch{{Field: "Temperature", Expected: "float64", Got: "string"}},
+			},
+		},
+		"pools": {
+			payload: `{` + status + `,"POOLS":[{"POOL":0,"URL":"stratum+tcp://a:3333","Status":"Alive","Priority":0,` +
+				`"Accepted":5,"Rejected":0,"Stale":0,"Difficulty Accepted":5.0,"User":"u","Stratum Active":"true","Bad Work":0}]}`,
+			call: func(miner *CGMiner) error {
+				_, err := miner.Pools()
+				return err
+			},
+			fails: true,
+			drift: SchemaDrift{
+				Type:       "Pool",
+				Section:    "POOLS",
+				Unknown:    []string{"Bad Work"},
+				Missing:    []string{"Last Share Time"},
+				Mismatches: []FieldMismatch{{Field: "Stratum Active", Expected: "bool", Got: "string"}},
+			},
+		},
+		"stats": {
+			payload: `{` + status + `,"STATS":[{"STATS":0,"ID":"GPU0","Name":"trm","GPU Power":120.5,"Calls":"0"}]}`,
+			call: func(miner *CGMiner) error {
+				_, err := miner.Stats()
+				return err
+			},
+			// vendor-specific keys don't fail strict mode
+			fails: false,
+			drift: SchemaDrift{
+				Type:       "GenericStats",
+				Section:    "STATS",
+				Unknown:    []string{"GPU Power", "Name"},
+				Missing:    []string{"Elapsed"},
+				Mismatches: []FieldMismatch{{Field: "Calls", Expected: "int", Got: "string"}},
+			},
+		},
+	}
+
+	for command, c := range cases {
+		t.Run(command, func(t *testing.T) {
+			var reported *SchemaDriftError
+			transport := JSONTransport{Schema: &SchemaOptions{Strict: true, OnSchemaDrift: func(drift *SchemaDriftError) {
+				reported = drift
+			}}}
+
+			err := callMiner(t, transport, c.payload, c.call)
+			var drift *SchemaDriftError
+			if errors.As(err, &drift) != c.fails {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !c.fails && err != nil {
+				t.Fatal(err)
+			}
+
+			expected := &SchemaDriftError{Command: command, Drifts: []SchemaDrift{c.drift}}
+			if diff := deep.Equal(reported, expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
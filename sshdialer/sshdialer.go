@@ -0,0 +1,283 @@
+// Package sshdialer provides cgminer.Dialer implementation which forwards
+// API connections through SSH jump host (bastion).
+//
+// Single Dialer keeps one SSH connection to the bastion and multiplexes
+// all forwarded connections over it, so the same Dialer can be shared
+// between many cgminer.CGMiner instances behind the same host.
+package sshdialer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+var _ cgminer.Dialer = (*Dialer)(nil)
+
+// ErrNoHostKeyCallback is returned when SSH config has no host key verification set.
+var ErrNoHostKeyCallback = errors.New("sshdialer: HostKeyCallback is required")
+
+// Config is SSH jump host connection config
+type Config struct {
+	// Address is SSH server address (host:port)
+	Address string
+
+	// User is SSH user name
+	User string
+
+	// Auth is list of authentication methods.
+	//
+	// See PrivateKey, PrivateKeyFile and Agent helpers.
+	Auth []ssh.AuthMethod
+
+	// HostKeyCallback verifies SSH server host key.
+	//
+	// See KnownHosts and FixedHostKey helpers.
+	HostKeyCallback ssh.HostKeyCallback
+
+	// Timeout is SSH connection and handshake timeout
+	Timeout time.Duration
+}
+
+// Dialer is cgminer.Dialer which forwards connections through SSH server.
+//
+// Dialer establishes SSH connection lazily on first dial and reuses it
+// for subsequent dials. Broken connection is re-established automatically.
+//
+// Dialer is safe for concurrent use.
+type Dialer struct {
+	cfg Config
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// New returns a new SSH dialer
+func New(cfg Config) (*Dialer, error) {
+	if cfg.HostKeyCallback == nil {
+		return nil, ErrNoHostKeyCallback
+	}
+
+	return &Dialer{cfg: cfg}, nil
+}
+
+// Dial implements cgminer.Dialer
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext implements cgminer.Dialer
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	client, err := d.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialClient(ctx, client, network, address)
+	if err == nil || ctx.Err() != nil {
+		return conn, err
+	}
+
+	// Rejected channel or bad address doesn't affect other connections
+	// multiplexed over the same client, so client is dropped only
+	// when SSH connection itself is broken.
+	if err != io.EOF && keepAlive(ctx, client) == nil {
+		return nil, err
+	}
+
+	// SSH connection might be dropped since last use,
+	// reconnect and try once again.
+	d.dropClient(client)
+
+	if client, err = d.getClient(ctx); err != nil {
+		return nil, err
+	}
+	return dialClient(ctx, client, network, address)
+}
+
+// Close closes underlying SSH connection
+func (d *Dialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client == nil {
+		return nil
+	}
+
+	err := d.client.Close()
+	d.client = nil
+	return err
+}
+
+func (d *Dialer) getClient(ctx context.Context) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil {
+		return d.client, nil
+	}
+
+	client, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d.client = client
+	go func() {
+		// forget connection as soon as it's closed by remote side
+		_ = client.Wait()
+		d.dropClient(client)
+	}()
+	return client, nil
+}
+
+// dropClient closes and forgets passed client if it's still current one.
+func (d *Dialer) dropClient(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_ = client.Close()
+	if d.client == client {
+		d.client = nil
+	}
+}
+
+func (d *Dialer) connect(ctx context.Context) (*ssh.Client, error) {
+	nd := net.Dialer{Timeout: d.cfg.Timeout}
+	conn, err := nd.DialContext(ctx, "tcp", d.cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("sshdialer: failed to connect to %s: %w", d.cfg.Address, err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if d.cfg.Timeout > 0 && (!ok || time.Until(deadline) > d.cfg.Timeout) {
+		deadline, ok = time.Now().Add(d.cfg.Timeout), true
+	}
+	if ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, d.cfg.Address, &ssh.ClientConfig{
+		User:            d.cfg.User,
+		Auth:            d.cfg.Auth,
+		HostKeyCallback: d.cfg.HostKeyCallback,
+		Timeout:         d.cfg.Timeout,
+	})
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("sshdialer: handshake with %s failed: %w", d.cfg.Address, err)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// dialClient opens forwarded connection and respects context cancellation,
+// as ssh.Client.Dial doesn't accept context.
+func dialClient(ctx context.Context, client *ssh.Client, network, address string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+
+	ch := make(chan result, 1)
+	go func() {
+		conn, err := client.Dial(network, address)
+		ch <- result{conn: conn, err: err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.conn, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-ch; r.conn != nil {
+				_ = r.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// keepAlive checks that SSH connection is alive by sending global request,
+// as OpenSSH does for ServerAliveInterval.
+func keepAlive(ctx context.Context, client *ssh.Client) error {
+	ch := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		ch <- err
+	}()
+
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PrivateKey returns public key auth method from PEM-encoded private key.
+//
+// Passphrase can be empty for unencrypted keys.
+func PrivateKey(pemBytes, passphrase []byte) (ssh.AuthMethod, error) {
+	var (
+		signer ssh.Signer
+		err    error
+	)
+	if len(passphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(pemBytes, passphrase)
+	} else {
+		signer, err = ssh.ParsePrivateKey(pemBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sshdialer: failed to parse private key: %w", err)
+	}
+
+	return ssh.PublicKeys(signer), nil
+}
+
+// PrivateKeyFile returns public key auth method from private key file.
+func PrivateKeyFile(path string, passphrase []byte) (ssh.AuthMethod, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return PrivateKey(data, passphrase)
+}
+
+// Agent returns auth method which uses SSH agent available at SSH_AUTH_SOCK.
+//
+// Returned closer should be called when auth method is not needed anymore.
+func Agent() (ssh.AuthMethod, func() error, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, nil, errors.New("sshdialer: SSH_AUTH_SOCK is not set")
+	}
+
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sshdialer: failed to connect to SSH agent: %w", err)
+	}
+
+	return ssh.PublicKeysCallback(agent.NewClient(conn).Signers), conn.Close, nil
+}
+
+// KnownHosts returns host key callback which verifies host keys using
+// OpenSSH known_hosts files.
+func KnownHosts(files ...string) (ssh.HostKeyCallback, error) {
+	return knownhosts.New(files...)
+}
+
+// FixedHostKey returns host key callback which accepts only passed key.
+func FixedHostKey(key ssh.PublicKey) ssh.HostKeyCallback {
+	return ssh.FixedHostKey(key)
+}
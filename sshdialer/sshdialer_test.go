@@ -0,0 +1,351 @@
+package sshdialer
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+	"golang.org/x/crypto/ssh"
+)
+
+const testTimeout = 5 * time.Second
+
+// sshServer is in-process SSH server which supports only "direct-tcpip" channels
+type sshServer struct {
+	listener net.Listener
+	hostKey  ssh.Signer
+	conns    int32
+
+	mu      sync.Mutex
+	sshConn []*ssh.ServerConn
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func startSSHServer(t *testing.T, clientKey ssh.PublicKey) *sshServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &sshServer{listener: l, hostKey: newSigner(t)}
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientKey.Marshal()) {
+				return nil, errors.New("unknown public key")
+			}
+			return nil, nil
+		},
+	}
+	cfg.AddHostKey(srv.hostKey)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn, cfg)
+		}
+	}()
+
+	t.Cleanup(srv.close)
+	return srv
+}
+
+func (s *sshServer) serve(conn net.Conn, cfg *ssh.ServerConfig) {
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	atomic.AddInt32(&s.conns, 1)
+	s.mu.Lock()
+	s.sshConn = append(s.sshConn, sshConn)
+	s.mu.Unlock()
+
+	go ssh.DiscardRequests(reqs)
+	for ch := range chans {
+		if ch.ChannelType() != "direct-tcpip" {
+			_ = ch.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+
+		var payload struct {
+			Host     string
+			Port     uint32
+			OrigHost string
+			OrigPort uint32
+		}
+		if err := ssh.Unmarshal(ch.ExtraData(), &payload); err != nil {
+			_ = ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			_ = ch.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		channel, chReqs, err := ch.Accept()
+		if err != nil {
+			_ = target.Close()
+			continue
+		}
+
+		go ssh.DiscardRequests(chReqs)
+		go func() {
+			_, _ = io.Copy(target, channel)
+			_ = target.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(channel, target)
+			_ = channel.Close()
+		}()
+	}
+}
+
+// dropConnections closes all established SSH connections
+func (s *sshServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.sshConn {
+		_ = c.Close()
+	}
+	s.sshConn = nil
+}
+
+func (s *sshServer) close() {
+	_ = s.listener.Close()
+	s.dropConnections()
+}
+
+func startMiner(t *testing.T, fixture string) string {
+	payload, err := ioutil.ReadFile(path.Join("..", "testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload = append(payload, 0x00)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				// wait for command before reply
+				_, _ = bufio.NewReader(conn).ReadBytes('\n')
+				_, _ = conn.Write(payload)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func newTestMiner(addr string, dialer cgminer.Dialer) *cgminer.CGMiner {
+	return &cgminer.CGMiner{
+		Address:   addr,
+		Timeout:   testTimeout,
+		Dialer:    dialer,
+		Transport: cgminer.NewJSONTransport(),
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{Address: "localhost:22"}); err != ErrNoHostKeyCallback {
+		t.Fatalf("expected ErrNoHostKeyCallback, got %v", err)
+	}
+}
+
+func TestDialer_ReusesConnection(t *testing.T) {
+	clientKey := newSigner(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	minerAddr := startMiner(t, "TestVersion.json")
+
+	dialer, err := New(Config{
+		Address:         srv.listener.Addr().String(),
+		User:            "miner",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(clientKey)},
+		HostKeyCallback: FixedHostKey(srv.hostKey.PublicKey()),
+		Timeout:         testTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.Close()
+
+	for i := 0; i < 3; i++ {
+		version, err := newTestMiner(minerAddr, dialer).Version()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version.Type != "Antminer S9" {
+			t.Fatalf("unexpected version response: %+v", version)
+		}
+	}
+
+	if n := atomic.LoadInt32(&srv.conns); n != 1 {
+		t.Fatalf("expected single SSH connection, got %d", n)
+	}
+}
+
+func TestDialer_Reconnect(t *testing.T) {
+	clientKey := newSigner(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	minerAddr := startMiner(t, "TestVersion.json")
+
+	dialer, err := New(Config{
+		Address:         srv.listener.Addr().String(),
+		User:            "miner",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(clientKey)},
+		HostKeyCallback: FixedHostKey(srv.hostKey.PublicKey()),
+		Timeout:         testTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.Close()
+
+	miner := newTestMiner(minerAddr, dialer)
+	if _, err := miner.Version(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.dropConnections()
+	if _, err := miner.Version(); err != nil {
+		t.Fatal("failed to reconnect:", err)
+	}
+
+	if n := atomic.LoadInt32(&srv.conns); n != 2 {
+		t.Fatalf("expected 2 SSH connections, got %d", n)
+	}
+}
+
+func TestDialer_HostKeyMismatch(t *testing.T) {
+	clientKey := newSigner(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	minerAddr := startMiner(t, "TestVersion.json")
+
+	dialer, err := New(Config{
+		Address:         srv.listener.Addr().String(),
+		User:            "miner",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(clientKey)},
+		HostKeyCallback: FixedHostKey(newSigner(t).PublicKey()),
+		Timeout:         testTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.Close()
+
+	_, err = newTestMiner(minerAddr, dialer).Version()
+	if _, ok := err.(cgminer.ConnectError); !ok {
+		t.Fatalf("expected ConnectError, got %T (%v)", err, err)
+	}
+}
+
+func TestDialer_TargetUnreachable(t *testing.T) {
+	clientKey := newSigner(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+
+	dialer, err := New(Config{
+		Address:         srv.listener.Addr().String(),
+		User:            "miner",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(clientKey)},
+		HostKeyCallback: FixedHostKey(srv.hostKey.PublicKey()),
+		Timeout:         testTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.Close()
+
+	_, err = dialer.Dial("tcp", "127.0.0.1:1")
+	var chanErr *ssh.OpenChannelError
+	if !errors.As(err, &chanErr) {
+		t.Fatalf("expected OpenChannelError, got %T (%v)", err, err)
+	}
+
+	if n := atomic.LoadInt32(&srv.conns); n != 1 {
+		t.Fatalf("SSH connection should not be re-established, got %d connections", n)
+	}
+}
+
+func TestDialer_DialErrorKeepsConnection(t *testing.T) {
+	clientKey := newSigner(t)
+	srv := startSSHServer(t, clientKey.PublicKey())
+	minerAddr := startMiner(t, "TestVersion.json")
+
+	dialer, err := New(Config{
+		Address:         srv.listener.Addr().String(),
+		User:            "miner",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(clientKey)},
+		HostKeyCallback: FixedHostKey(srv.hostKey.PublicKey()),
+		Timeout:         testTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.Close()
+
+	conn, err := dialer.Dial("tcp", minerAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, addr := range []struct{ network, address string }{
+		{"udp", minerAddr},
+		{"tcp", "no-port"},
+		{"tcp", "127.0.0.1:1"},
+	} {
+		if _, err := dialer.Dial(addr.network, addr.address); err == nil {
+			t.Fatalf("%s %s: expected dial error", addr.network, addr.address)
+		}
+	}
+
+	// connection opened before failed dials still works
+	_ = conn.SetDeadline(time.Now().Add(testTimeout))
+	if _, err := io.WriteString(conn, `{"command":"version"}`+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadAll(conn); err != nil {
+		t.Fatal("forwarded connection is broken:", err)
+	}
+
+	if n := atomic.LoadInt32(&srv.conns); n != 1 {
+		t.Fatalf("SSH connection should not be re-established, got %d connections", n)
+	}
+}
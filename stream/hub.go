@@ -0,0 +1,292 @@
+// Package stream pushes miner poll results and health events to clients
+// over Server-Sent Events and WebSocket.
+//
+// Poller polls miners and publishes events to Hub, Server delivers them to
+// subscribed HTTP clients, applying per-client filters.
+package stream
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// DefaultBufferSize is default number of events buffered per subscriber
+const DefaultBufferSize = 16
+
+// Event types
+const (
+	SnapshotEvent = "snapshot"
+	HealthEvent   = "health"
+)
+
+// Health event kinds
+const (
+	Unreachable    = "unreachable"
+	Recovered      = "recovered"
+	Restarted      = "restarted"
+	DeviceReset    = "device_reset"
+	CountersZeroed = "counters_zeroed"
+)
+
+// Health describes miner health change
+type Health struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message,omitempty"`
+
+	// GPU is device index for device_reset events
+	GPU *int64 `json:"gpu,omitempty"`
+}
+
+// Event is message delivered to subscribers
+type Event struct {
+	Type  string    `json:"type"`
+	Miner string    `json:"miner"`
+	Time  time.Time `json:"time"`
+
+	Summary *cgminer.Summary `json:"summary,omitempty"`
+	Devs    []cgminer.Devs   `json:"devs,omitempty"`
+	Health  *Health          `json:"health,omitempty"`
+}
+
+// Filter selects events delivered to subscriber. Empty fields match everything.
+type Filter struct {
+	// Miners is list of miner IDs
+	Miners []string
+
+	// Types is list of event types
+	Types []string
+
+	// GPUs limits devices included into snapshot events
+	GPUs []int64
+
+	// Fields limits summary and device fields, by JSON name (e.g. "MHS av")
+	Fields []string
+}
+
+func (f Filter) match(e Event) bool {
+	return (len(f.Miners) == 0 || containsString(f.Miners, e.Miner)) &&
+		(len(f.Types) == 0 || containsString(f.Types, e.Type))
+}
+
+// encode returns JSON of event with filter applied
+func (f Filter) encode(e Event, dropped uint64) ([]byte, error) {
+	if len(f.GPUs) > 0 && e.Devs != nil {
+		devs := make([]cgminer.Devs, 0, len(f.GPUs))
+		for _, d := range e.Devs {
+			if containsInt(f.GPUs, d.GPU) {
+				devs = append(devs, d)
+			}
+		}
+		e.Devs = devs
+	}
+
+	type message struct {
+		Event
+		Summary interface{} `json:"summary,omitempty"`
+		Devs    interface{} `json:"devs,omitempty"`
+		Dropped uint64      `json:"dropped,omitempty"`
+	}
+
+	msg := message{Event: e, Dropped: dropped}
+	if e.Summary != nil {
+		msg.Summary = e.Summary
+	}
+	if e.Devs != nil {
+		msg.Devs = e.Devs
+	}
+
+	if len(f.Fields) > 0 {
+		if e.Summary != nil {
+			s, err := selectFields(e.Summary, f.Fields)
+			if err != nil {
+				return nil, err
+			}
+			msg.Summary = s
+		}
+		if e.Devs != nil {
+			// GPU index is always kept to identify device
+			fields := append(append([]string(nil), f.Fields...), "GPU")
+			devs := make([]map[string]json.RawMessage, 0, len(e.Devs))
+			for _, d := range e.Devs {
+				m, err := selectFields(d, fields)
+				if err != nil {
+					return nil, err
+				}
+				devs = append(devs, m)
+			}
+			msg.Devs = devs
+		}
+	}
+	return json.Marshal(msg)
+}
+
+func selectFields(v interface{}, fields []string) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			selected[f] = v
+		}
+	}
+	return selected, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int64, i int64) bool {
+	for _, v := range list {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+// Hub fans out events to subscribers.
+//
+// Publishing never blocks: when subscriber buffer is full, the oldest
+// buffered event is dropped and subscriber is told how many events
+// were lost with the next delivered one.
+type Hub struct {
+	// BufferSize is number of events buffered per subscriber,
+	// DefaultBufferSize is used if zero.
+	BufferSize int
+
+	mu    sync.Mutex
+	subs  map[*Subscription]struct{}
+	ready chan struct{}
+}
+
+// NewHub returns new hub
+func NewHub() *Hub {
+	return &Hub{BufferSize: DefaultBufferSize}
+}
+
+// Subscription receives events matching filter
+type Subscription struct {
+	Filter Filter
+
+	hub     *Hub
+	ch      chan Event
+	mu      sync.Mutex
+	dropped uint64
+}
+
+// Subscribe registers new subscription
+func (h *Hub) Subscribe(f Filter) *Subscription {
+	size := h.BufferSize
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	s := &Subscription{Filter: f, hub: h, ch: make(chan Event, size)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs == nil {
+		h.subs = make(map[*Subscription]struct{})
+	}
+	h.subs[s] = struct{}{}
+	if len(h.subs) == 1 {
+		close(h.readyLocked())
+	}
+	return s
+}
+
+// Publish delivers event to matching subscribers
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subs {
+		if s.Filter.match(e) {
+			s.push(e)
+		}
+	}
+}
+
+// Subscribers returns number of active subscriptions
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// active returns channel which is closed while hub has subscribers
+func (h *Hub) active() <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.readyLocked()
+}
+
+func (h *Hub) readyLocked() chan struct{} {
+	if h.ready == nil {
+		h.ready = make(chan struct{})
+	}
+	return h.ready
+}
+
+func (s *Subscription) push(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		select {
+		case s.ch <- e:
+			return
+		default:
+		}
+
+		// buffer is full, drop the oldest event
+		select {
+		case <-s.ch:
+			s.dropped++
+		default:
+		}
+	}
+}
+
+// Events returns channel of events, closed by Close
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// takeDropped returns and resets number of dropped events
+func (s *Subscription) takeDropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.dropped
+	s.dropped = 0
+	return n
+}
+
+// Close unregisters subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		delete(s.hub.subs, s)
+		close(s.ch)
+		if len(s.hub.subs) == 0 {
+			s.hub.ready = make(chan struct{})
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+// DefaultPollInterval is default interval between miner polls
+const DefaultPollInterval = 10 * time.Second
+
+// Poller periodically polls miners and publishes snapshot
+// and health events to Hub.
+//
+// Miners are polled only while Hub has subscribers: polling starts
+// on the first subscription and stops when the last one is closed.
+type Poller struct {
+	Hub *Hub
+
+	// Miners maps miner ID to client
+	Miners map[string]*cgminer.CGMiner
+
+	// Interval is time between polls, DefaultPollInterval is used if zero
+	Interval time.Duration
+
+	tracker *cgminer.RestartTracker
+	mu      sync.Mutex
+	down    map[string]bool
+}
+
+// NewPoller returns poller publishing to hub
+func NewPoller(hub *Hub, miners map[string]*cgminer.CGMiner, interval time.Duration) *Poller {
+	return &Poller{Hub: hub, Miners: miners, Interval: interval}
+}
+
+// Run polls miners while Hub has subscribers until context is cancelled
+func (p *Poller) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.Hub.active():
+		}
+
+		p.PollOnce(ctx)
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// PollOnce polls all miners concurrently and publishes events
+func (p *Poller) PollOnce(ctx context.Context) {
+	p.mu.Lock()
+	if p.tracker == nil {
+		p.tracker = cgminer.NewRestartTracker()
+		p.down = make(map[string]bool)
+	}
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for id, miner := range p.Miners {
+		wg.Add(1)
+		go func(id string, miner *cgminer.CGMiner) {
+			defer wg.Done()
+			p.poll(ctx, id, miner)
+		}(id, miner)
+	}
+	wg.Wait()
+}
+
+func (p *Poller) poll(ctx context.Context, id string, miner *cgminer.CGMiner) {
+	summary, err := miner.SummaryContext(ctx)
+	var devs *[]cgminer.Devs
+	if err == nil {
+		devs, err = miner.DevsContext(ctx)
+	}
+	now := time.Now()
+
+	p.mu.Lock()
+	wasDown := p.down[id]
+	p.down[id] = err != nil
+	p.mu.Unlock()
+
+	if err != nil {
+		if !wasDown {
+			p.Hub.Publish(Event{Type: HealthEvent, Miner: id, Time: now, Health: &Health{Kind: Unreachable, Message: err.Error()}})
+		}
+		return
+	}
+	if wasDown {
+		p.Hub.Publish(Event{Type: HealthEvent, Miner: id, Time: now, Health: &Health{Kind: Recovered}})
+	}
+
+	for _, e := range p.tracker.Observe(id, cgminer.Poll{Time: now, Summary: summary, Devs: *devs}) {
+		p.Hub.Publish(Event{Type: HealthEvent, Miner: id, Time: now, Health: restartHealth(e)})
+	}
+	p.Hub.Publish(Event{Type: SnapshotEvent, Miner: id, Time: now, Summary: summary, Devs: *devs})
+}
+
+func restartHealth(e cgminer.RestartEvent) *Health {
+	switch e.Type {
+	case cgminer.MinerRestarted:
+		return &Health{Kind: Restarted, Message: fmt.Sprintf("elapsed went from %d to %d", e.PrevElapsed, e.Elapsed)}
+	case cgminer.DeviceReset:
+		gpu := e.GPU
+		return &Health{Kind: DeviceReset, GPU: &gpu, Message: fmt.Sprintf("device elapsed went from %d to %d", e.PrevElapsed, e.Elapsed)}
+	default:
+		return &Health{Kind: CountersZeroed}
+	}
+}
@@ -0,0 +1,222 @@
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultKeepAlive is default interval of keep-alive messages
+const DefaultKeepAlive = 15 * time.Second
+
+// Server streams hub events to HTTP clients.
+//
+// WebSocket upgrade requests are served as WebSocket streams,
+// other requests as Server-Sent Events. WebSocket requests sent by browsers
+// are accepted only from request host or AllowedOrigins.
+//
+// Query parameters define subscription filter:
+//
+//	miner   miner ID, repeatable or comma-separated
+//	group   miner group name from Server.Groups, repeatable
+//	type    event type ("snapshot" or "health")
+//	gpu     device index to include into snapshots
+//	fields  summary and device fields by JSON name, e.g. "MHS av,Temperature"
+type Server struct {
+	Hub *Hub
+
+	// Groups maps group name to miner IDs
+	Groups map[string][]string
+
+	// KeepAlive is interval of keep-alive messages,
+	// DefaultKeepAlive is used if zero.
+	KeepAlive time.Duration
+
+	// AllowedOrigins lists origins, e.g. "https://dash.example.com",
+	// which may open WebSocket streams besides request host.
+	// "*" allows any origin.
+	AllowedOrigins []string
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	f, err := s.filter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if isWebSocketUpgrade(r) {
+		s.serveWebSocket(w, r, f)
+		return
+	}
+	s.serveSSE(w, r, f)
+}
+
+func (s *Server) keepAlive() time.Duration {
+	if s.KeepAlive > 0 {
+		return s.KeepAlive
+	}
+	return DefaultKeepAlive
+}
+
+// filter builds subscription filter from query
+func (s *Server) filter(r *http.Request) (Filter, error) {
+	q := r.URL.Query()
+
+	var f Filter
+	f.Miners = splitValues(q["miner"])
+	for _, g := range splitValues(q["group"]) {
+		miners, ok := s.Groups[g]
+		if !ok {
+			return f, fmt.Errorf("unknown group %q", g)
+		}
+		f.Miners = append(f.Miners, miners...)
+	}
+	if len(q["group"]) > 0 && len(f.Miners) == 0 {
+		return f, fmt.Errorf("groups have no miners")
+	}
+
+	f.Types = splitValues(q["type"])
+	for _, t := range f.Types {
+		if t != SnapshotEvent && t != HealthEvent {
+			return f, fmt.Errorf("unknown event type %q", t)
+		}
+	}
+
+	for _, v := range splitValues(q["gpu"]) {
+		gpu, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return f, fmt.Errorf("invalid gpu %q", v)
+		}
+		f.GPUs = append(f.GPUs, gpu)
+	}
+
+	f.Fields = splitValues(q["fields"])
+	return f, nil
+}
+
+func splitValues(values []string) []string {
+	var result []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+func (s *Server) serveSSE(w http.ResponseWriter, r *http.Request, f Filter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := s.Hub.Subscribe(f)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.keepAlive())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+
+			data, err := f.encode(e, sub.takeDropped())
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// checkOrigin reports whether WebSocket request came from allowed origin.
+// Requests without Origin header are sent by non-browser clients.
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, o := range s.AllowedOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request, f Filter) {
+	if !s.checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sub := s.Hub.Subscribe(f)
+	defer sub.Close()
+
+	ticker := time.NewTicker(s.keepAlive())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.done:
+			return
+		case <-ticker.C:
+			if err := conn.writeFrame(opPing, nil); err != nil {
+				return
+			}
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+
+			data, err := f.encode(e, sub.takeDropped())
+			if err != nil {
+				continue
+			}
+			if err := conn.writeFrame(opText, data); err != nil {
+				return
+			}
+		}
+	}
+}
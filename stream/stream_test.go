@@ -0,0 +1,336 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cgminer "github.com/sokdak/go-teamredminer-api"
+)
+
+func snapshot(miner string) Event {
+	return Event{
+		Type:    SnapshotEvent,
+		Miner:   miner,
+		Time:    time.Unix(1600000000, 0).UTC(),
+		Summary: &cgminer.Summary{Elapsed: 10, MHSav: 50},
+		Devs:    []cgminer.Devs{{GPU: 0, Temperature: 60}, {GPU: 1, Temperature: 70}},
+	}
+}
+
+func TestHub_Backpressure(t *testing.T) {
+	hub := &Hub{BufferSize: 2}
+	sub := hub.Subscribe(Filter{Miners: []string{"rig1"}})
+	defer sub.Close()
+
+	for i := 0; i < 5; i++ {
+		e := snapshot("rig1")
+		e.Summary.Elapsed = int64(i)
+		hub.Publish(e)
+	}
+	hub.Publish(snapshot("rig2"))
+
+	e := <-sub.Events()
+	if e.Summary.Elapsed != 3 {
+		t.Fatalf("expected oldest events to be dropped, got elapsed %d", e.Summary.Elapsed)
+	}
+	if n := sub.takeDropped(); n != 3 {
+		t.Fatalf("expected 3 dropped events, got %d", n)
+	}
+	if e = <-sub.Events(); e.Summary.Elapsed != 4 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	sub.Close()
+	if hub.Subscribers() != 0 {
+		t.Fatal("subscription should be removed")
+	}
+}
+
+func TestFilter_Encode(t *testing.T) {
+	f := Filter{GPUs: []int64{1}, Fields: []string{"Temperature", "Elapsed"}}
+	data, err := f.encode(snapshot("rig1"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"type":"snapshot","miner":"rig1","time":"2020-09-13T12:26:40Z",` +
+		`"summary":{"Elapsed":10},"devs":[{"GPU":1,"Temperature":70}],"dropped":2}`
+	if string(data) != expected {
+		t.Fatalf("unexpected message:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestServer_SSE(t *testing.T) {
+	hub := NewHub()
+	srv := httptest.NewServer(&Server{Hub: hub, Groups: map[string][]string{"north": {"rig1"}}})
+	defer srv.Close()
+
+	if rsp, err := http.Get(srv.URL + "?group=south"); err != nil || rsp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown group, got %v %v", rsp, err)
+	}
+
+	rsp, err := http.Get(srv.URL + "?group=north&type=snapshot&gpu=0&fields=Temperature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type: %s", rsp.Header.Get("Content-Type"))
+	}
+
+	waitSubscribers(t, hub, 1)
+	hub.Publish(snapshot("rig2"))
+	hub.Publish(Event{Type: HealthEvent, Miner: "rig1", Health: &Health{Kind: Unreachable}})
+	hub.Publish(snapshot("rig1"))
+
+	r := bufio.NewReader(rsp.Body)
+	event, _ := r.ReadString('\n')
+	data, _ := r.ReadString('\n')
+	if event != "event: snapshot\n" || !strings.Contains(data, `"miner":"rig1"`) ||
+		!strings.Contains(data, `"devs":[{"GPU":0,"Temperature":60}]`) || !strings.Contains(data, `"summary":{}`) {
+		t.Fatalf("unexpected event:\n%s%s", event, data)
+	}
+}
+
+func TestServer_WebSocket(t *testing.T) {
+	hub := NewHub()
+	srv := httptest.NewServer(&Server{Hub: hub})
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, _ = io.WriteString(conn, "GET /?miner=rig1 HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+
+	r := bufio.NewReader(conn)
+	rsp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.StatusCode != http.StatusSwitchingProtocols || rsp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected handshake response: %d %v", rsp.StatusCode, rsp.Header)
+	}
+
+	waitSubscribers(t, hub, 1)
+	hub.Publish(snapshot("rig1"))
+
+	op, payload := readServerFrame(t, r)
+	var msg map[string]json.RawMessage
+	if op != opText || json.Unmarshal(payload, &msg) != nil || string(msg["miner"]) != `"rig1"` {
+		t.Fatalf("unexpected frame: %x %s", op, payload)
+	}
+
+	// masked ping from client is answered with pong
+	_, _ = conn.Write([]byte{0x80 | opPing, 0x80 | 2, 1, 2, 3, 4, 'h' ^ 1, 'i' ^ 2})
+	if op, payload = readServerFrame(t, r); op != opPong || string(payload) != "hi" {
+		t.Fatalf("expected pong, got %x %q", op, payload)
+	}
+
+	// close frame ends subscription
+	_, _ = conn.Write([]byte{0x80 | opClose, 0x80, 0, 0, 0, 0})
+	if op, _ = readServerFrame(t, r); op != opClose {
+		t.Fatalf("expected close, got %x", op)
+	}
+	waitSubscribers(t, hub, 0)
+}
+
+func TestServer_WebSocketOrigin(t *testing.T) {
+	srv := httptest.NewServer(&Server{Hub: NewHub(), AllowedOrigins: []string{"https://dash.example.com"}})
+	defer srv.Close()
+
+	handshake := func(origin string) int {
+		t.Helper()
+		conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		_, _ = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: gw.local:8080\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+			"Origin: "+origin+"\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+		rsp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+		return rsp.StatusCode
+	}
+
+	for origin, code := range map[string]int{
+		"http://gw.local:8080":     http.StatusSwitchingProtocols,
+		"https://dash.example.com": http.StatusSwitchingProtocols,
+		"https://evil.example.com": http.StatusForbidden,
+		"http://gw.local":          http.StatusForbidden,
+	} {
+		if got := handshake(origin); got != code {
+			t.Errorf("%s: expected %d, got %d", origin, code, got)
+		}
+	}
+}
+
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var hdr [2]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		t.Fatal(err)
+	}
+
+	length := int(hdr[1] & 0x7f)
+	if length == 126 {
+		var ext [2]byte
+		_, _ = io.ReadFull(r, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return hdr[0] & 0x0f, payload
+}
+
+func waitSubscribers(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if hub.Subscribers() == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d subscribers, got %d", n, hub.Subscribers())
+}
+
+// fakeMiner answers summary and devs commands, failing when down is set
+type fakeMiner struct {
+	mu      sync.Mutex
+	down    bool
+	elapsed int64
+	dials   int
+}
+
+func (f *fakeMiner) miner() *cgminer.CGMiner {
+	m := cgminer.NewCGMiner("127.0.0.1", 4028, 5*time.Second)
+	m.Dialer = cgminer.DialerFunc(func(ctx context.Context, network, address string) (net.Conn, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.dials++
+		if f.down {
+			return nil, errors.New("connection refused")
+		}
+
+		client, server := net.Pipe()
+		go f.serve(server, f.elapsed)
+		return client, nil
+	})
+	return m
+}
+
+func (f *fakeMiner) serve(conn net.Conn, elapsed int64) {
+	defer conn.Close()
+	var cmd cgminer.Command
+	if err := json.NewDecoder(conn).Decode(&cmd); err != nil {
+		return
+	}
+
+	rsp := `{"STATUS":[{"STATUS":"S"}],"DEVS":[{"GPU":0}],"id":1}`
+	if cmd.Command == "summary" {
+		rsp = `{"STATUS":[{"STATUS":"S"}],"SUMMARY":[{"Elapsed":` + strconv.FormatInt(elapsed, 10) + `}],"id":1}`
+	}
+	_, _ = conn.Write(append([]byte(rsp), 0x00))
+}
+
+func (f *fakeMiner) dialCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dials
+}
+
+func (f *fakeMiner) set(down bool, elapsed int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+	f.elapsed = elapsed
+}
+
+func TestPoller(t *testing.T) {
+	fake := &fakeMiner{elapsed: 5}
+	hub := &Hub{BufferSize: 10}
+	sub := hub.Subscribe(Filter{})
+	defer sub.Close()
+
+	p := NewPoller(hub, map[string]*cgminer.CGMiner{"rig1": fake.miner()}, time.Second)
+	ctx := context.Background()
+
+	p.PollOnce(ctx)
+	fake.set(true, 0)
+	p.PollOnce(ctx)
+	p.PollOnce(ctx)
+	fake.set(false, 1)
+	p.PollOnce(ctx)
+
+	var got []string
+	for len(sub.Events()) > 0 {
+		e := <-sub.Events()
+		if e.Health != nil {
+			got = append(got, e.Health.Kind)
+		} else {
+			got = append(got, e.Type)
+		}
+	}
+
+	expected := []string{SnapshotEvent, Unreachable, Recovered, Restarted, SnapshotEvent}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Fatalf("unexpected events: %v", got)
+	}
+}
+
+func TestPoller_RunOnlyWithSubscribers(t *testing.T) {
+	fake := &fakeMiner{elapsed: 5}
+	hub := NewHub()
+	p := NewPoller(hub, map[string]*cgminer.CGMiner{"rig1": fake.miner()}, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	if n := fake.dialCount(); n != 0 {
+		t.Fatalf("expected no polls without subscribers, got %d dials", n)
+	}
+
+	sub := hub.Subscribe(Filter{Types: []string{SnapshotEvent}})
+	select {
+	case <-sub.Events():
+	case <-time.After(time.Second):
+		t.Fatal("expected snapshot after subscribe")
+	}
+
+	sub.Close()
+	time.Sleep(50 * time.Millisecond) // let in-flight poll finish
+	n := fake.dialCount()
+	time.Sleep(50 * time.Millisecond)
+	if m := fake.dialCount(); m != n {
+		t.Fatalf("expected polling to stop after last subscriber, dials went from %d to %d", n, m)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected Run error: %v", err)
+	}
+}
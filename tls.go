@@ -0,0 +1,219 @@
+package cgminer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+)
+
+var _ Dialer = (*TLSDialer)(nil)
+
+// ErrPinMismatch is returned when server certificate chain doesn't contain any pinned public key
+var ErrPinMismatch = errors.New("certificate chain doesn't match any pinned public key")
+
+// TLSHandshakeError represents TLS handshake error.
+//
+// Returned by TLSDialer and wrapped by CGMiner into ConnectError,
+// use errors.As to distinguish it from plain connection errors.
+type TLSHandshakeError struct {
+	// Address is remote address
+	Address string
+
+	err error
+}
+
+// Error implements error
+func (err TLSHandshakeError) Error() string {
+	return fmt.Sprintf("TLS handshake with %s failed: %s", err.Address, err.err)
+}
+
+// Unwrap implements error
+func (err TLSHandshakeError) Unwrap() error {
+	return err.err
+}
+
+// TLSDialer is Dialer which wraps connections into TLS.
+//
+// Useful when miner API is exposed through stunnel or similar TLS-terminating proxy.
+type TLSDialer struct {
+	// Dialer is underlying network dialer
+	Dialer Dialer
+
+	// Config is TLS client config.
+	//
+	// If ServerName is empty, it's derived from dialed address.
+	Config *tls.Config
+
+	// PinnedKeys is optional list of base64-encoded SHA-256 hashes
+	// of certificate public keys (SPKI), same format as used by HPKP.
+	//
+	// When set, at least one certificate in the verified chain should match one of pins.
+	PinnedKeys []string
+}
+
+// NewTLSDialer returns TLS dialer on top of net.Dialer
+func NewTLSDialer(cfg *tls.Config, timeout time.Duration) *TLSDialer {
+	return &TLSDialer{
+		Dialer: &net.Dialer{Timeout: timeout},
+		Config: cfg,
+	}
+}
+
+// Dial implements Dialer
+func (d *TLSDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext implements Dialer
+func (d *TLSDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := d.clientConfig(address)
+	if err != nil {
+		_ = conn.Close()
+		return nil, TLSHandshakeError{Address: address, err: err}
+	}
+
+	tlsConn := tls.Client(conn, cfg)
+	if err = handshakeContext(ctx, tlsConn); err != nil {
+		_ = conn.Close()
+		return nil, TLSHandshakeError{Address: address, err: err}
+	}
+
+	return tlsConn, nil
+}
+
+func (d *TLSDialer) clientConfig(address string) (*tls.Config, error) {
+	var cfg *tls.Config
+	if d.Config != nil {
+		cfg = d.Config.Clone()
+	} else {
+		cfg = new(tls.Config)
+	}
+
+	if cfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		cfg.ServerName = host
+	}
+
+	if len(d.PinnedKeys) == 0 {
+		return cfg, nil
+	}
+
+	pins := make(map[[sha256.Size]byte]struct{}, len(d.PinnedKeys))
+	for _, pin := range d.PinnedKeys {
+		raw, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(raw) != sha256.Size {
+			return nil, fmt.Errorf("invalid public key pin %q", pin)
+		}
+
+		var sum [sha256.Size]byte
+		copy(sum[:], raw)
+		pins[sum] = struct{}{}
+	}
+
+	verify := cfg.VerifyPeerCertificate
+	cfg.VerifyPeerCertificate = func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+		if verify != nil {
+			if err := verify(rawCerts, chains); err != nil {
+				return err
+			}
+		}
+
+		// verified chains are empty when InsecureSkipVerify is set,
+		// check presented certificates in this case.
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, chain := range chains {
+			certs = append(certs, chain...)
+		}
+		if len(chains) == 0 {
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					return err
+				}
+				certs = append(certs, cert)
+			}
+		}
+
+		for _, cert := range certs {
+			if _, ok := pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)]; ok {
+				return nil
+			}
+		}
+		return ErrPinMismatch
+	}
+	return cfg, nil
+}
+
+// handshakeContext performs TLS handshake and aborts it when context is done
+func handshakeContext(ctx context.Context, conn *tls.Conn) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{}) //nolint
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- conn.Handshake()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		_ = conn.Close()
+		<-errCh
+		return ctx.Err()
+	}
+}
+
+// PublicKeyPin returns base64-encoded SHA-256 hash of certificate public key
+// suitable for TLSDialer.PinnedKeys
+func PublicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// LoadTLSConfig returns TLS client config from PEM files.
+//
+// If caFile is set, only server certificates issued by CAs from this file are trusted.
+// certFile and keyFile set client certificate and are optional.
+// serverName overrides SNI and certificate host name check.
+func LoadTLSConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{ServerName: serverName}
+	if caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %q", caFile)
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,214 @@
+package cgminer
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func (c testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert, isCA bool) testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		DNSNames:              []string{name},
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	parentCert, parentKey := tpl, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testCert{cert: cert, key: key}
+}
+
+// mockTLSProxy starts stunnel-like TLS-terminating proxy in front of miner
+// which replies with passed payload.
+func mockTLSProxy(t *testing.T, serverCert testCert, clientCA *x509.Certificate, payload []byte) string {
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCA)
+
+	l, err := tls.Listen(proto, ip+":0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert.tlsCertificate()},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		// report client certificate errors during handshake
+		MaxVersion: tls.VersionTLS12,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, err := bufio.NewReader(conn).ReadBytes('\n'); err != nil && err != io.EOF {
+					return
+				}
+				_, _ = conn.Write(payload)
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestTLSDialer(t *testing.T) {
+	ca := newTestCert(t, "Test CA", nil, true)
+	otherCA := newTestCert(t, "Other CA", nil, true)
+	serverCert := newTestCert(t, "miner.internal", &ca, false)
+	clientCert := newTestCert(t, "poller", &ca, false)
+	addr := mockTLSProxy(t, serverCert, ca.cert, getFixture("TestVersion.json"))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	otherPool := x509.NewCertPool()
+	otherPool.AddCert(otherCA.cert)
+
+	cases := map[string]struct {
+		config       *tls.Config
+		pins         []string
+		handshakeErr bool
+	}{
+		"ok": {
+			config: &tls.Config{
+				RootCAs:      pool,
+				Certificates: []tls.Certificate{clientCert.tlsCertificate()},
+				ServerName:   "miner.internal",
+			},
+		},
+		"ok with pin": {
+			config: &tls.Config{
+				RootCAs:      pool,
+				Certificates: []tls.Certificate{clientCert.tlsCertificate()},
+				ServerName:   "miner.internal",
+			},
+			pins: []string{PublicKeyPin(ca.cert)},
+		},
+		"pin mismatch": {
+			config: &tls.Config{
+				RootCAs:      pool,
+				Certificates: []tls.Certificate{clientCert.tlsCertificate()},
+				ServerName:   "miner.internal",
+			},
+			pins:         []string{PublicKeyPin(otherCA.cert)},
+			handshakeErr: true,
+		},
+		"untrusted CA": {
+			config: &tls.Config{
+				RootCAs:      otherPool,
+				Certificates: []tls.Certificate{clientCert.tlsCertificate()},
+				ServerName:   "miner.internal",
+			},
+			handshakeErr: true,
+		},
+		"no SNI override": {
+			config: &tls.Config{
+				RootCAs:      pool,
+				Certificates: []tls.Certificate{clientCert.tlsCertificate()},
+			},
+			handshakeErr: true,
+		},
+		"no client certificate": {
+			config: &tls.Config{
+				RootCAs:    pool,
+				ServerName: "miner.internal",
+			},
+			handshakeErr: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			dialer := NewTLSDialer(c.config, minerTimeout)
+			dialer.PinnedKeys = c.pins
+			miner := NewCGMiner(ip, 0, minerTimeout)
+			miner.Address = addr
+			miner.Dialer = dialer
+
+			version, err := miner.Version()
+			if !c.handshakeErr {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if version.Type != "Antminer S9" {
+					t.Fatalf("unexpected version response: %+v", version)
+				}
+				return
+			}
+
+			if _, ok := err.(ConnectError); !ok {
+				t.Fatalf("expected ConnectError, got %T (%v)", err, err)
+			}
+			var tlsErr TLSHandshakeError
+			if !errors.As(err, &tlsErr) {
+				t.Fatalf("expected TLSHandshakeError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSDialer_PlainConnectError(t *testing.T) {
+	l, err := net.Listen(proto, ip+":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	miner := NewCGMiner(ip, 0, minerTimeout)
+	miner.Address = addr
+	miner.Dialer = NewTLSDialer(nil, minerTimeout)
+
+	_, err = miner.Version()
+	if _, ok := err.(ConnectError); !ok {
+		t.Fatalf("expected ConnectError, got %T (%v)", err, err)
+	}
+	var tlsErr TLSHandshakeError
+	if errors.As(err, &tlsErr) {
+		t.Fatalf("connection error should not be reported as TLS handshake error: %v", err)
+	}
+}
@@ -4,10 +4,17 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net"
 )
 
+// DefaultMaxResponseSize is default response size limit
+const DefaultMaxResponseSize = 8 << 20
+
+// ErrResponseTooLarge is returned when API response exceeds size limit
+var ErrResponseTooLarge = errors.New("cgminer: response is too large")
+
 // AbstractResponse is generic command response which provides execution status
 type AbstractResponse interface {
 	// HasError returns status error
@@ -34,40 +41,76 @@ type Transport interface {
 
 var _ Transport = (*JSONTransport)(nil)
 
-type JSONTransport struct{}
+// JSONTransport is JSON API transport
+type JSONTransport struct {
+	// Repairer fixes malformed responses before decoding.
+	//
+	// When nil, response is decoded as a stream and only "stats"
+	// response concatenation is fixed.
+	Repairer *Repairer
+
+	// Schema enables response schema checks, nil disables them
+	Schema *SchemaOptions
+}
+
+// SchemaOptions configures JSONTransport response schema checks
+type SchemaOptions struct {
+	// Strict makes DecodeResponse return *SchemaDriftError when response
+	// contains unknown keys, misses required keys (see RequiredFields)
+	// or has values which don't match struct field types.
+	//
+	// "stats" responses are never checked strictly, as their keys are
+	// vendor-specific, drift is only reported to OnSchemaDrift.
+	Strict bool
+
+	// OnSchemaDrift is optional hook called when response doesn't match
+	// response type. Allows to log drift without failing requests in lenient mode.
+	OnSchemaDrift func(drift *SchemaDriftError)
+}
 
 // NewJSONTransport returns JSON encoding/decoding transport
 func NewJSONTransport() JSONTransport {
 	return JSONTransport{}
 }
 
+// NewTolerantJSONTransport returns JSON transport which repairs
+// malformed responses using DefaultRepairRules.
+//
+// onRepair is optional debug hook which receives names of applied repairs.
+func NewTolerantJSONTransport(onRepair func(cmd Command, applied []string)) JSONTransport {
+	r := NewRepairer(DefaultRepairRules...)
+	r.OnRepair = onRepair
+	return JSONTransport{Repairer: r}
+}
+
 // SendCommand implements Transport interface
 func (t JSONTransport) SendCommand(conn net.Conn, cmd Command) error {
 	return json.NewEncoder(conn).Encode(cmd)
 }
 
-// DecodeResponse implements Transport interface
+// DecodeResponse implements Transport interface.
+//
+// Response is decoded directly from connection without buffering whole reply,
+// unless repair or schema check is enabled.
 func (t JSONTransport) DecodeResponse(conn net.Conn, cmd Command, out AbstractResponse) error {
-	rsp, err := readWithNullTerminator(conn)
-	if err != nil && err != io.EOF {
-		return err
+	if t.Repairer != nil || t.Schema != nil {
+		return t.decodeBuffered(conn, cmd, out)
 	}
 
+	var r io.Reader = nullTerminatedReader{r: conn}
+
 	// fix incorrect json response from miner ("}{")
 	if cmd.Command == "stats" {
-		rsp = bytes.Replace(rsp, []byte("}{"), []byte(","), 1)
+		r = &statsFixReader{r: bufio.NewReader(r)}
 	}
 
 	isEmpty := out == nil
 	if isEmpty {
-		if len(rsp) == 0 {
-			return nil
-		}
 		out = new(GenericResponse)
 	}
 
-	if err := json.Unmarshal(rsp, out); err != nil {
-		if isEmpty {
+	if err := json.NewDecoder(r).Decode(out); err != nil {
+		if isEmpty && isDecodeError(err) {
 			// just omit error if consumer passed empty response output
 			return nil
 		}
@@ -77,6 +120,118 @@ func (t JSONTransport) DecodeResponse(conn net.Conn, cmd Command, out AbstractRe
 	return out.HasError()
 }
 
+// decodeBuffered reads whole response, repairs and decodes it
+// and checks schema drift.
+func (t JSONTransport) decodeBuffered(conn net.Conn, cmd Command, out AbstractResponse) error {
+	rsp, err := readWithNullTerminator(conn)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	isEmpty := out == nil
+	if isEmpty {
+		if len(rsp) == 0 {
+			return nil
+		}
+		out = new(GenericResponse)
+	}
+
+	if t.Repairer != nil {
+		rsp, _ = t.Repairer.Repair(cmd, rsp)
+	} else {
+		rsp, _ = repairStatsConcat(cmd, rsp)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	err = json.Unmarshal(rsp, out)
+	switch {
+	case err == nil:
+	case isEmpty:
+		// just omit error if consumer passed empty response output
+		return nil
+	case errors.As(err, &typeErr) && t.Schema != nil:
+		// type mismatch is reported as schema drift
+	default:
+		return err
+	}
+
+	if err := out.HasError(); err != nil {
+		return err
+	}
+
+	if isEmpty || t.Schema == nil {
+		return nil
+	}
+
+	drift := checkSchema(cmd, rsp, out)
+	if drift == nil {
+		// should not happen, but don't lose decode error
+		return err
+	}
+	if t.Schema.OnSchemaDrift != nil {
+		t.Schema.OnSchemaDrift(drift)
+	}
+	if t.Schema.Strict && cmd.Command != "stats" {
+		return drift
+	}
+	return nil
+}
+
+// isDecodeError reports whether error is caused by malformed or empty response
+// and not by connection failure.
+func isDecodeError(err error) bool {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	return err == io.EOF || err == io.ErrUnexpectedEOF ||
+		errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// nullTerminatedReader reads cgminer response and reports io.EOF
+// at null terminator (0x00)
+type nullTerminatedReader struct {
+	r io.Reader
+}
+
+func (r nullTerminatedReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if i := bytes.IndexByte(p[:n], 0x00); i != -1 {
+		return i, io.EOF
+	}
+	return n, err
+}
+
+// statsFixReader replaces first "}{" sequence in stream with ","
+type statsFixReader struct {
+	r     *bufio.Reader
+	fixed bool
+}
+
+func (s *statsFixReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if s.fixed || n == 0 {
+		return n, err
+	}
+
+	if i := bytes.Index(p[:n], []byte("}{")); i != -1 {
+		p[i] = ','
+		copy(p[i+1:], p[i+2:n])
+		s.fixed = true
+		return n - 1, err
+	}
+
+	// sequence might be split between reads
+	if p[n-1] == '}' {
+		if next, _ := s.r.Peek(1); len(next) == 1 && next[0] == '{' {
+			p[n-1] = ','
+			_, _ = s.r.Discard(1)
+			s.fixed = true
+		}
+	}
+	return n, err
+}
+
 // readWithNullTerminator reads cgminer response, but stops
 // at null terminator (0x00)
 func readWithNullTerminator(r io.Reader) ([]byte, error) {
@@ -87,3 +242,28 @@ func readWithNullTerminator(r io.Reader) ([]byte, error) {
 
 	return bytes.TrimRight(result, "\x00"), nil
 }
+
+// limitedConn is net.Conn which fails with ErrResponseTooLarge
+// when more than n bytes are read
+type limitedConn struct {
+	net.Conn
+	n int64
+}
+
+func (c *limitedConn) Read(p []byte) (int, error) {
+	if c.n <= 0 {
+		// check if there is more data
+		var b [1]byte
+		if n, err := c.Conn.Read(b[:]); n == 0 {
+			return 0, err
+		}
+		return 0, ErrResponseTooLarge
+	}
+
+	if int64(len(p)) > c.n {
+		p = p[:c.n]
+	}
+	n, err := c.Conn.Read(p)
+	c.n -= int64(n)
+	return n, err
+}
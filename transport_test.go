@@ -0,0 +1,88 @@
+package cgminer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStatsFixReader(t *testing.T) {
+	cases := map[string]string{
+		`{"STATS":[{"a":1}{"b":2}]}`:        `{"STATS":[{"a":1,"b":2}]}`,
+		`{"STATS":[{"a":1}{"b":2}{"c":3}]}`: `{"STATS":[{"a":1,"b":2}{"c":3}]}`,
+		`{"STATS":[{"a":1}]}`:               `{"STATS":[{"a":1}]}`,
+	}
+
+	for input, want := range cases {
+		t.Run(input, func(t *testing.T) {
+			// read one byte at a time to check sequence split between reads
+			r := &statsFixReader{r: bufio.NewReader(iotest.OneByteReader(strings.NewReader(input)))}
+			got, err := ioutil.ReadAll(iotest.OneByteReader(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Fatalf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestNullTerminatedReader(t *testing.T) {
+	got, err := ioutil.ReadAll(nullTerminatedReader{r: strings.NewReader("{}\x00garbage")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "{}" {
+		t.Fatalf("unexpected result: %q", got)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	payload := getFixture("TestSummary.json")
+	cases := map[string]struct {
+		limit int64
+		err   error
+	}{
+		"default":   {},
+		"unlimited": {limit: -1},
+		"exact":     {limit: int64(len(payload))},
+		"too small": {limit: int64(len(payload)) / 2, err: ErrResponseTooLarge},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, finish := context.WithCancel(context.Background())
+			defer finish()
+			port := getPort()
+			go mockTCPServer(ctx, ip, port, payload)
+			wait(1)
+
+			miner := NewCGMiner(ip, port, minerTimeout)
+			miner.MaxResponseSize = c.limit
+			_, err := miner.Summary()
+			if !errors.Is(err, c.err) {
+				t.Fatalf("expected %v, got %v", c.err, err)
+			}
+		})
+	}
+}
+
+func TestMaxResponseSize_RawCall(t *testing.T) {
+	payload := getFixture("TestSummary.json")
+	ctx, finish := context.WithCancel(context.Background())
+	defer finish()
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, payload)
+	wait(1)
+
+	miner := NewCGMiner(ip, port, minerTimeout)
+	miner.MaxResponseSize = 16
+	if _, err := miner.RawCall(context.Background(), NewCommandWithoutParameter("summary")); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
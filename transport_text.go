@@ -0,0 +1,285 @@
+package cgminer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var _ Transport = (*TextTransport)(nil)
+
+// textSections maps command name to response section name
+// used by JSON API.
+var textSections = map[string]string{
+	"version":    "VERSION",
+	"summary":    "SUMMARY",
+	"devs":       "DEVS",
+	"pools":      "POOLS",
+	"stats":      "STATS",
+	"devdetails": "DEVDETAILS",
+	"config":     "CONFIG",
+	"coin":       "COIN",
+	"notify":     "NOTIFY",
+	"gpu":        "GPU",
+	"estats":     "STATS",
+}
+
+// TextTransport is plain-text API transport.
+//
+// Request is sent as "command|parameter" and response
+// has "KEY=value,KEY=value|..." format, where first section is status.
+type TextTransport struct{}
+
+// NewTextTransport returns plain-text encoding/decoding transport
+func NewTextTransport() TextTransport {
+	return TextTransport{}
+}
+
+// SendCommand implements Transport interface
+func (t TextTransport) SendCommand(conn net.Conn, cmd Command) error {
+	req := cmd.Command
+	if cmd.Parameter != "" {
+		req += "|" + cmd.Parameter
+	}
+
+	_, err := io.WriteString(conn, req)
+	return err
+}
+
+// DecodeResponse implements Transport interface
+func (t TextTransport) DecodeResponse(conn net.Conn, cmd Command, out AbstractResponse) error {
+	rsp, err := readWithNullTerminator(conn)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	isEmpty := out == nil
+	if isEmpty {
+		if len(rsp) == 0 {
+			return nil
+		}
+		out = new(GenericResponse)
+	}
+
+	if err := decodeText(string(rsp), cmd.Command, out); err != nil {
+		if isEmpty {
+			// just omit error if consumer passed empty response output
+			return nil
+		}
+		return err
+	}
+
+	return out.HasError()
+}
+
+// parseText splits plain-text response into list of sections
+func parseText(rsp string) []map[string]string {
+	var sections []map[string]string
+	for _, part := range strings.Split(rsp, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		section := make(map[string]string)
+		for _, field := range strings.Split(part, ",") {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				// section name without value, e.g. "SUMMARY"
+				continue
+			}
+			section[kv[0]] = kv[1]
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// decodeText decodes plain-text response into JSON-tagged response struct
+func decodeText(rsp, command string, out interface{}) error {
+	sections := parseText(rsp)
+	if len(sections) == 0 {
+		return fmt.Errorf("empty response for command %q", command)
+	}
+	if _, ok := sections[0]["STATUS"]; !ok {
+		return fmt.Errorf("no status section in response for command %q", command)
+	}
+
+	name := textSections[command]
+	if name == "" {
+		name = strings.ToUpper(command)
+	}
+
+	if r, ok := out.(*Response); ok {
+		return decodeTextResponse(r, name, sections)
+	}
+
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("cannot decode response into %T", out)
+	}
+
+	return decodeTextSections(v.Elem(), map[string][]map[string]string{
+		"STATUS": sections[:1],
+		name:     sections[1:],
+	})
+}
+
+// decodeTextResponse decodes plain-text response into generic response.
+//
+// All values are stored as strings, Value getters convert them on demand.
+func decodeTextResponse(r *Response, name string, sections []map[string]string) error {
+	*r = Response{
+		Status:   make([]Status, 1),
+		Sections: make(map[string][]map[string]Value, 1),
+	}
+	if err := decodeTextRecord(reflect.ValueOf(&r.Status[0]).Elem(), sections[0]); err != nil {
+		return err
+	}
+
+	if len(sections) == 1 {
+		return nil
+	}
+
+	records := make([]map[string]Value, 0, len(sections)-1)
+	for _, section := range sections[1:] {
+		record := make(map[string]Value, len(section))
+		for k, v := range section {
+			record[k] = StringOf(v)
+		}
+		records = append(records, record)
+	}
+	r.Sections[name] = records
+	return nil
+}
+
+func decodeTextSections(v reflect.Value, sections map[string][]map[string]string) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			if err := decodeTextSections(v.Field(i), sections); err != nil {
+				return err
+			}
+			continue
+		}
+
+		records, ok := sections[fieldName(f)]
+		if !ok || f.Type.Kind() != reflect.Slice {
+			continue
+		}
+
+		slice := reflect.MakeSlice(f.Type, len(records), len(records))
+		for j, record := range records {
+			if err := decodeTextRecord(slice.Index(j), record); err != nil {
+				return err
+			}
+		}
+		v.Field(i).Set(slice)
+	}
+	return nil
+}
+
+func decodeTextRecord(v reflect.Value, record map[string]string) error {
+	if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+		// generic records like map[string]interface{}
+		raw, _ := json.Marshal(record)
+		return json.Unmarshal(raw, v.Addr().Interface())
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode section into %s", v.Type())
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		val, ok := record[fieldName(f)]
+		if !ok {
+			continue
+		}
+		if err := setTextValue(v.Field(i), val); err != nil {
+			return fmt.Errorf("cannot decode field %q: %w", fieldName(f), err)
+		}
+	}
+	return nil
+}
+
+func setTextValue(v reflect.Value, val string) error {
+	if u, ok := v.Addr().Interface().(json.Unmarshaler); ok {
+		return u.UnmarshalJSON([]byte(strconv.Quote(val)))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(val)
+	case reflect.Bool:
+		switch strings.ToLower(val) {
+		case "y", "true", "1":
+			v.SetBool(true)
+		case "n", "false", "0", "":
+			v.SetBool(false)
+		default:
+			return fmt.Errorf("invalid boolean %q", val)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if val == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if val == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		if val == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(val))
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// fieldName returns field name as used in API response
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	switch tag {
+	case "":
+		return f.Name
+	case "-":
+		return ""
+	}
+
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
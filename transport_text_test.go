@@ -0,0 +1,49 @@
+package cgminer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestTextTransport(t *testing.T) {
+	payload := []byte("STATUS=S,When=1521044526,Code=9,Msg=3 GPU(s),Description=teamredminer 0.9.4|" +
+		"GPU=0,Enabled=Y,Status=Alive,Temperature=61.00,Fan Percent=55,MHS av=28.51,Accepted=512,Rejected=1,Hardware Errors=0|" +
+		"GPU=1,Enabled=N,Status=Sick,Temperature=0.00,Fan Percent=0,MHS av=0.00,Accepted=0,Rejected=0,Hardware Errors=3|\x00")
+	expected := []Devs{
+		{GPU: 0, Enabled: "Y", Status: "Alive", Temperature: 61, FanPercent: 55, MHSav: 28.51, Accepted: 512, Rejected: 1, AcceptedShares: 512, RejectedShares: 1},
+		{GPU: 1, Enabled: "N", Status: "Sick", HardwareErrors: 3},
+	}
+
+	ctx, finish := context.WithCancel(context.Background())
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, payload)
+	wait(1)
+	miner := NewCGMiner(ip, port, minerTimeout)
+	miner.Transport = NewTextTransport()
+	devs, err := miner.Devs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(*devs, expected); diff != nil {
+		t.Error(diff)
+	}
+	finish()
+	wait(1)
+}
+
+func TestTextTransport_StatusError(t *testing.T) {
+	payload := []byte("STATUS=E,When=1521044526,Code=14,Msg=Invalid command,Description=teamredminer 0.9.4|\x00")
+	ctx, finish := context.WithCancel(context.Background())
+	port := getPort()
+	go mockTCPServer(ctx, ip, port, payload)
+	wait(1)
+	miner := NewCGMiner(ip, port, minerTimeout)
+	miner.Transport = NewTextTransport()
+	if _, err := miner.Summary(); err == nil {
+		t.Fatal("expected API error")
+	}
+	finish()
+	wait(1)
+}
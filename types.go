@@ -781,6 +781,67 @@ type DeviceDetail struct {
 	DevicePath string `json:"Device Path"`
 }
 
+// Config - miner configuration summary
+type Config struct {
+	GPUCount     int64  `json:"GPU Count"`
+	ASCCount     int64  `json:"ASC Count"`
+	PGACount     int64  `json:"PGA Count"`
+	PoolCount    int64  `json:"Pool Count"`
+	Strategy     string `json:"Strategy"`
+	LogInterval  int64  `json:"Log Interval"`
+	DeviceCode   string `json:"Device Code"`
+	OS           string `json:"OS"`
+	FailoverOnly bool   `json:"Failover-Only"`
+	ScanTime     int64  `json:"ScanTime"`
+	Queue        int64  `json:"Queue"`
+	Expiry       int64  `json:"Expiry"`
+	Hotplug      string `json:"Hotplug,omitempty"`
+}
+
+// Coin - currently mined coin and network info
+type Coin struct {
+	HashMethod        string  `json:"Hash Method"`
+	CurrentBlockTime  float64 `json:"Current Block Time"`
+	CurrentBlockHash  string  `json:"Current Block Hash"`
+	LP                bool    `json:"LP"`
+	NetworkDifficulty float64 `json:"Network Difficulty"`
+}
+
+// Notify - per-device health notification info
+type Notify struct {
+	Notify           int64  `json:"NOTIFY"`
+	Name             string `json:"Name"`
+	ID               int64  `json:"ID"`
+	LastWell         int64  `json:"Last Well"`
+	LastNotWell      int64  `json:"Last Not Well"`
+	ReasonNotWell    string `json:"Reason Not Well"`
+	ThreadFailInit   int64  `json:"*Thread Fail Init"`
+	ThreadZeroHash   int64  `json:"*Thread Zero Hash"`
+	ThreadFailQueue  int64  `json:"*Thread Fail Queue"`
+	DevSickIdle60s   int64  `json:"*Dev Sick Idle 60s"`
+	DevDeadIdle600s  int64  `json:"*Dev Dead Idle 600s"`
+	DevNostart       int64  `json:"*Dev Nostart"`
+	DevOverHeat      int64  `json:"*Dev Over Heat"`
+	DevThermalCutoff int64  `json:"*Dev Thermal Cutoff"`
+	DevCommsError    int64  `json:"*Dev Comms Error"`
+	DevThrottle      int64  `json:"*Dev Throttle"`
+}
+
+type configResponse struct {
+	GenericResponse
+	Config []Config `json:"CONFIG"`
+}
+
+type coinResponse struct {
+	GenericResponse
+	Coin []Coin `json:"COIN"`
+}
+
+type notifyResponse struct {
+	GenericResponse
+	Notify []Notify `json:"NOTIFY"`
+}
+
 type statsResponse struct {
 	GenericResponse
 	Stats []GenericStats `json:"STATS"`
@@ -796,6 +857,11 @@ type devsResponse struct {
 	Devs []Devs `json:"DEVS"`
 }
 
+type gpuResponse struct {
+	GenericResponse
+	GPU []Devs `json:"GPU"`
+}
+
 type poolsResponse struct {
 	GenericResponse
 	Pools []Pool `json:"POOLS"`
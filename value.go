@@ -0,0 +1,267 @@
+package cgminer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValueKind is kind of value stored in Value
+type ValueKind int
+
+const (
+	// NullValue is null or absent value
+	NullValue ValueKind = iota
+
+	// NumberValue is numeric value
+	NumberValue
+
+	// StringValue is string value
+	StringValue
+
+	// BoolValue is boolean value
+	BoolValue
+
+	// RawValue is nested object or array
+	RawValue
+)
+
+// Value is generic API response value which can be Number, string or bool.
+//
+// Typed getters convert value if possible, e.g. string "12.5"
+// can be read as number and "Y" as boolean.
+type Value struct {
+	kind ValueKind
+	num  Number
+	str  string
+	b    bool
+	raw  json.RawMessage
+}
+
+// NumberOf returns numeric value
+func NumberOf(n Number) Value {
+	return Value{kind: NumberValue, num: n}
+}
+
+// StringOf returns string value
+func StringOf(s string) Value {
+	return Value{kind: StringValue, str: s}
+}
+
+// BoolOf returns boolean value
+func BoolOf(b bool) Value {
+	return Value{kind: BoolValue, b: b}
+}
+
+// Kind returns value kind
+func (v Value) Kind() ValueKind {
+	return v.kind
+}
+
+// IsNull reports whether value is null or absent
+func (v Value) IsNull() bool {
+	return v.kind == NullValue
+}
+
+// Number returns value as Number.
+//
+// String values are parsed, booleans are converted to 0 or 1.
+func (v Value) Number() (Number, bool) {
+	switch v.kind {
+	case NumberValue:
+		return v.num, true
+	case StringValue:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.str), 64)
+		if err != nil {
+			return 0, false
+		}
+		return Number(f), true
+	case BoolValue:
+		if v.b {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// Float64 returns value as float64
+func (v Value) Float64() (float64, bool) {
+	n, ok := v.Number()
+	return n.Float64(), ok
+}
+
+// Int64 returns value as int64
+func (v Value) Int64() (int64, bool) {
+	n, ok := v.Number()
+	return n.Int64(), ok
+}
+
+// Bool returns value as bool.
+//
+// Strings "Y", "true" and "1" are treated as true, "N", "false" and "0" as false.
+// Non-zero numbers are true.
+func (v Value) Bool() (bool, bool) {
+	switch v.kind {
+	case BoolValue:
+		return v.b, true
+	case NumberValue:
+		return v.num != 0, true
+	case StringValue:
+		switch strings.ToLower(strings.TrimSpace(v.str)) {
+		case "y", "yes", "true", "1":
+			return true, true
+		case "n", "no", "false", "0":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// String returns value as string
+func (v Value) String() string {
+	switch v.kind {
+	case StringValue:
+		return v.str
+	case NumberValue:
+		return strconv.FormatFloat(float64(v.num), 'f', -1, 64)
+	case BoolValue:
+		return strconv.FormatBool(v.b)
+	case RawValue:
+		return string(v.raw)
+	default:
+		return ""
+	}
+}
+
+// Raw returns nested object or array as raw JSON
+func (v Value) Raw() (json.RawMessage, bool) {
+	return v.raw, v.kind == RawValue
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *Value) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		*v = Value{}
+		return nil
+	}
+
+	switch b[0] {
+	case 'n':
+		*v = Value{}
+	case 't', 'f':
+		var val bool
+		if err := json.Unmarshal(b, &val); err != nil {
+			return err
+		}
+		*v = BoolOf(val)
+	case '"':
+		var val string
+		if err := json.Unmarshal(b, &val); err != nil {
+			return err
+		}
+		*v = StringOf(val)
+	case '{', '[':
+		*v = Value{kind: RawValue, raw: append(json.RawMessage(nil), b...)}
+	default:
+		var n Number
+		if err := n.UnmarshalJSON(b); err != nil {
+			return err
+		}
+		*v = NumberOf(n)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (v Value) MarshalJSON() ([]byte, error) {
+	switch v.kind {
+	case NumberValue:
+		return []byte(v.String()), nil
+	case StringValue:
+		return json.Marshal(v.str)
+	case BoolValue:
+		return json.Marshal(v.b)
+	case RawValue:
+		return v.raw, nil
+	default:
+		return []byte("null"), nil
+	}
+}
+
+// Response is generic API response with all sections decoded
+// into key-value records.
+//
+// Useful for vendor-specific commands which have no dedicated struct.
+type Response struct {
+	ID     int
+	Status []Status
+
+	// Sections is map of section name (e.g. "SUMMARY") to list of records
+	Sections map[string][]map[string]Value
+}
+
+// HasError implements AbstractResponse interface
+func (r *Response) HasError() error {
+	return GenericResponse{ID: r.ID, Status: r.Status}.HasError()
+}
+
+// Section returns records of section with passed name.
+//
+// Name is matched case-insensitively.
+func (r *Response) Section(name string) []map[string]Value {
+	if records, ok := r.Sections[name]; ok {
+		return records
+	}
+
+	for key, records := range r.Sections {
+		if strings.EqualFold(key, name) {
+			return records
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *Response) UnmarshalJSON(b []byte) error {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(b, &sections); err != nil {
+		return err
+	}
+
+	*r = Response{Sections: make(map[string][]map[string]Value, len(sections))}
+	for key, raw := range sections {
+		switch key {
+		case "STATUS":
+			if err := json.Unmarshal(raw, &r.Status); err != nil {
+				return fmt.Errorf("cannot decode status: %w", err)
+			}
+		case "id":
+			_ = json.Unmarshal(raw, &r.ID)
+		default:
+			var records []map[string]Value
+			if err := json.Unmarshal(raw, &records); err != nil {
+				// skip sections which are not list of objects
+				continue
+			}
+			r.Sections[key] = records
+		}
+	}
+	return nil
+}
+
+// CallMap sends command to API and returns generic response.
+//
+// Response error check is performed as for other commands.
+func (c *CGMiner) CallMap(ctx context.Context, cmd Command) (*Response, error) {
+	resp := new(Response)
+	if err := c.CallContext(ctx, cmd, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
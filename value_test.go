@@ -0,0 +1,111 @@
+package cgminer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestValue(t *testing.T) {
+	type want struct {
+		kind   ValueKind
+		num    float64
+		numOK  bool
+		bool   bool
+		boolOK bool
+		str    string
+	}
+
+	cases := map[string]want{
+		`12.5`:     {kind: NumberValue, num: 12.5, numOK: true, bool: true, boolOK: true, str: "12.5"},
+		`0`:        {kind: NumberValue, numOK: true, boolOK: true, str: "0"},
+		`"61.5"`:   {kind: StringValue, num: 61.5, numOK: true, str: "61.5"},
+		`"Y"`:      {kind: StringValue, bool: true, boolOK: true, str: "Y"},
+		`"Alive"`:  {kind: StringValue, str: "Alive"},
+		`true`:     {kind: BoolValue, num: 1, numOK: true, bool: true, boolOK: true, str: "true"},
+		`null`:     {kind: NullValue},
+		`{"a": 1}`: {kind: RawValue, str: `{"a": 1}`},
+	}
+
+	for input, c := range cases {
+		t.Run(input, func(t *testing.T) {
+			var v Value
+			if err := json.Unmarshal([]byte(input), &v); err != nil {
+				t.Fatal(err)
+			}
+
+			if v.Kind() != c.kind {
+				t.Errorf("kind mismatch: %d != %d", v.Kind(), c.kind)
+			}
+			if num, ok := v.Float64(); num != c.num || ok != c.numOK {
+				t.Errorf("number mismatch: %f %t", num, ok)
+			}
+			if b, ok := v.Bool(); b != c.bool || ok != c.boolOK {
+				t.Errorf("bool mismatch: %t %t", b, ok)
+			}
+			if s := v.String(); s != c.str {
+				t.Errorf("string mismatch: %q", s)
+			}
+		})
+	}
+}
+
+func TestCallMap(t *testing.T) {
+	cases := map[string]struct {
+		transport Transport
+		payload   []byte
+	}{
+		"json": {
+			transport: NewJSONTransport(),
+			payload:   getFixture("TestEstats.json"),
+		},
+		"text": {
+			transport: NewTextTransport(),
+			payload: []byte("STATUS=S,When=1650000000,Code=70,Msg=CGMiner stats,Description=teamredminer 0.9.4|" +
+				"STATS=0,ID=GPU0,Elapsed=3600,GPU Temp=61.5,Fan=55,Enabled=true|\x00"),
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, finish := context.WithCancel(context.Background())
+			defer finish()
+			port := getPort()
+			go mockTCPServer(ctx, ip, port, c.payload)
+			wait(1)
+
+			miner := NewCGMiner(ip, port, minerTimeout)
+			miner.Transport = c.transport
+			rsp, err := miner.CallMap(context.Background(), NewCommandWithoutParameter("estats"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(rsp.Status) != 1 || rsp.Status[0].Code != 70 {
+				t.Fatalf("unexpected status: %+v", rsp.Status)
+			}
+
+			records := rsp.Section("stats")
+			if len(records) != 1 {
+				t.Fatalf("expected 1 record, got %d", len(records))
+			}
+
+			rec := records[0]
+			if id := rec["ID"].String(); id != "GPU0" {
+				t.Errorf("unexpected ID: %q", id)
+			}
+			if temp, _ := rec["GPU Temp"].Float64(); temp != 61.5 {
+				t.Errorf("unexpected temperature: %f", temp)
+			}
+			if fan, _ := rec["Fan"].Int64(); fan != 55 {
+				t.Errorf("unexpected fan: %d", fan)
+			}
+			if enabled, ok := rec["Enabled"].Bool(); !enabled || !ok {
+				t.Error("expected enabled device")
+			}
+			if !rec["Missing"].IsNull() {
+				t.Error("absent value should be null")
+			}
+		})
+	}
+}